@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
+)
+
+type budgetCmd struct {
+	sessionManager *tracker.SessionManager
+}
+
+// NewBudgetCmd creates a new budget command
+func NewBudgetCmd(sm *tracker.SessionManager) *cobra.Command {
+	c := &budgetCmd{
+		sessionManager: sm,
+	}
+	return &cobra.Command{
+		Use:   "budget",
+		Short: "Show the time used and left in each budget",
+		Long: `Show the time used and left in the current period of each budget set under
+budgets in the config file, and the time projected to be used by the end of
+the period at the rate so far.
+
+Budgets limit the time spent on a task, a tag or a project per day, week,
+month or year, e.g.
+
+  budgets:
+    client-a:
+      project: client-a
+      limit: 40h
+      period: month
+    learning:
+      tag: learning
+      limit: 5h
+      period: week
+
+start and stop warn when a budget reaches 80% and 100%.`,
+		Example: `  gotrack budget
+  gotrack budget --output json`,
+		Args: cobra.NoArgs,
+		RunE: c.run,
+	}
+}
+
+func (c *budgetCmd) run(cmd *cobra.Command, args []string) error {
+	sm := c.sessionManager
+	if sm == nil {
+		sm = GetSessionManager()
+		if sm == nil {
+			fmt.Println("No session manager available. Please ensure GoTrack is properly initialized.")
+			return fmt.Errorf("session manager not initialized")
+		}
+	}
+
+	ssns, err := sm.GetAllSessions()
+	if err != nil {
+		return fmt.Errorf("failed to get sessions: %v", err)
+	}
+
+	days, now := reportDays(), time.Now()
+	var statuses []analytics.BudgetStatus
+	for _, b := range budgets() {
+		statuses = append(statuses, analytics.CheckBudget(ssns, b, days, now))
+	}
+
+	if machineOutput() {
+		return writeOutput(newBudgetsOutput(statuses))
+	}
+	if len(statuses) == 0 {
+		fmt.Println("No budgets set, add them under budgets in " + paths.ConfigFile)
+		return nil
+	}
+	return writeBudgets(os.Stdout, statuses)
+}
+
+func writeBudgets(out io.Writer, statuses []analytics.BudgetStatus) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BUDGET\tFOR\tPERIOD\tUSED\tLIMIT\tLEFT\tPROJECTED")
+	for _, s := range statuses {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			s.Name,
+			budgetTarget(s.Budget),
+			s.Current.From.Format("Jan 2")+" - "+s.Current.To.Add(-time.Nanosecond).Format("Jan 2"),
+			formatDuration(s.Used),
+			formatDuration(s.Limit),
+			formatDuration(s.Remaining()),
+			formatDuration(s.Projected),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, s := range statuses {
+		if s.Share() >= analytics.BudgetThresholds[0] {
+			fmt.Fprintln(out, budgetWarning(s))
+		} else if s.Projected > s.Limit {
+			fmt.Fprintf(out, "%s is on track to use %s of %s by %s\n",
+				color.CyanString(s.Name), formatDuration(s.Projected), formatDuration(s.Limit), s.Current.To.Add(-time.Nanosecond).Format("Jan 2"))
+		}
+	}
+	return nil
+}
+
+// budgets returns the budgets of the config, ordered by name
+func budgets() []analytics.Budget {
+	var list []analytics.Budget
+	for name, b := range appConfig.Budgets {
+		list = append(list, analytics.Budget{
+			Name:    name,
+			Task:    b.Task,
+			Tag:     b.Tag,
+			Project: b.Project,
+			Limit:   time.Duration(b.Limit),
+			Period:  b.Period,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+func budgetTarget(b analytics.Budget) string {
+	switch {
+	case b.Task != "":
+		return "task " + b.Task
+	case b.Tag != "":
+		return "tag " + b.Tag
+	default:
+		return "project " + b.Project
+	}
+}
+
+func budgetWarning(s analytics.BudgetStatus) string {
+	period := "this " + s.Period
+	if s.Period == "day" {
+		period = "today"
+	}
+	msg := fmt.Sprintf("Budget %s is at %.0f%%: %s of %s used %s",
+		s.Name, 100*s.Share(), formatDuration(s.Used), formatDuration(s.Limit), period)
+	if s.Share() >= 1 {
+		return color.RedString(msg)
+	}
+	return color.YellowString(msg)
+}
+
+// warnBudgets prints a warning to stderr for every budget of the session
+// that reached 80% or 100% of its limit. Sessions that are starting warn
+// about budgets already past a threshold, stopped sessions about the ones
+// they pushed past it.
+func warnBudgets(sm *tracker.SessionManager, session *models.Session) {
+	list := budgets()
+	if len(list) == 0 {
+		return
+	}
+	ssns, err := sm.GetAllSessions()
+	if err != nil {
+		return
+	}
+
+	days, now := reportDays(), time.Now()
+	for _, b := range list {
+		if !b.Matches(*session) {
+			continue
+		}
+		s := analytics.CheckBudget(ssns, b, days, now)
+		var before time.Duration
+		if !session.IsActive() {
+			before = s.UsedWithout(*session, now)
+		}
+		if _, crossed := s.Crossed(before); crossed {
+			fmt.Fprintln(os.Stderr, budgetWarning(s))
+		}
+	}
+}
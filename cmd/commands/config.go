@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/AndriyBarskyi/gotrack/internal/config"
+)
+
+// allowInvalidConfig is the annotation of commands that still run when the
+// config file does not pass validation
+const allowInvalidConfig = "allow-invalid-config"
+
+// NewConfigCmd creates a new config command
+func NewConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "View and change the GoTrack configuration",
+		Long: `View and change the settings in the config file, see 'gotrack config path'.
+
+Settings are addressed by dotted keys such as pomodoro.work_duration. Values
+are checked against the type of the setting, and changes keep the comments
+in the file.`,
+		Example: `
+  gotrack config list
+  gotrack config get pomodoro.work_duration
+  gotrack config set pomodoro.work_duration 50m
+  gotrack config set pomodoro.notifications.enabled false
+  gotrack config unset pomodoro.work_duration
+  gotrack config edit
+`,
+		Args: cobra.NoArgs,
+		Annotations: map[string]string{
+			allowInvalidConfig: "true",
+		},
+	}
+
+	cmd.AddCommand(NewConfigValidateCmd())
+	cmd.AddCommand(newConfigGetCmd())
+	cmd.AddCommand(newConfigListCmd())
+	cmd.AddCommand(newConfigSetCmd())
+	cmd.AddCommand(newConfigUnsetCmd())
+	cmd.AddCommand(newConfigEditCmd())
+	cmd.AddCommand(newConfigPathCmd())
+	cmd.AddCommand(newConfigResetCmd())
+
+	// Errors are about settings, not about how the command was called
+	for _, sub := range cmd.Commands() {
+		sub.SilenceUsage = true
+	}
+
+	return cmd
+}
+
+// NewConfigValidateCmd creates a new config validate command
+func NewConfigValidateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Check the config file for invalid settings",
+		Long: `Check every setting in the config file and the GOTRACK_* environment
+overrides, and report the ones that are invalid together with their line in
+the file.`,
+		Args: cobra.NoArgs,
+		Annotations: map[string]string{
+			allowInvalidConfig: "true",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, err := loadConfig(paths.ConfigFile)
+			if err == nil {
+				if machineOutput() {
+					return writeOutput(newValidationOutput(nil))
+				}
+				fmt.Println(color.GreenString("Configuration is valid"))
+				return nil
+			}
+
+			var verr *config.ValidationError
+			if !errors.As(err, &verr) {
+				return err
+			}
+			if machineOutput() {
+				if err := writeOutput(newValidationOutput(verr)); err != nil {
+					return err
+				}
+				return fmt.Errorf("configuration has %d invalid setting(s)", len(verr.Errors))
+			}
+			for _, fe := range verr.Errors {
+				fmt.Printf("%s %s\n", color.RedString("✗"), fe.Error())
+			}
+			return fmt.Errorf("configuration has %d invalid setting(s)", len(verr.Errors))
+		},
+	}
+}
+
+func newConfigGetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print the value of a setting",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			value, err := appConfig.Get(args[0])
+			if err != nil {
+				return err
+			}
+			if machineOutput() {
+				return writeOutput(settingsOutput{newSettingOutput(args[0], value)})
+			}
+			fmt.Println(value)
+			return nil
+		},
+	}
+}
+
+func newConfigListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "Print every setting with its value",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings, err := appConfig.Settings()
+			if err != nil {
+				return err
+			}
+			if machineOutput() {
+				out := make(settingsOutput, len(settings))
+				for i, s := range settings {
+					out[i] = newSettingOutput(s.Key, s.Value)
+				}
+				return writeOutput(out)
+			}
+			for _, s := range settings {
+				source := ""
+				if slices.Contains(envOverrides, s.Key) {
+					source = " (from " + config.EnvName(s.Key) + ")"
+				}
+				fmt.Printf("%s = %s%s\n", color.CyanString(s.Key), s.Value, source)
+			}
+			return nil
+		},
+	}
+}
+
+func newConfigSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Change a setting",
+		Args:  cobra.ExactArgs(2),
+		Annotations: map[string]string{
+			allowInvalidConfig: "true",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			doc, err := config.OpenDocument(paths.ConfigFile)
+			if err != nil {
+				return err
+			}
+			if err := doc.Set(args[0], args[1]); err != nil {
+				return err
+			}
+			if err := doc.Save(); err != nil {
+				return fmt.Errorf("failed to save config: %v", err)
+			}
+
+			cfg, err := doc.Config()
+			if err != nil {
+				if machineOutput() {
+					return err
+				}
+				fmt.Printf("Updated %s, but other settings are still invalid:\n%v\n", color.CyanString(args[0]), err)
+				return nil
+			}
+			value, err := cfg.Get(args[0])
+			if err != nil {
+				return err
+			}
+			if machineOutput() {
+				return writeOutput(settingsOutput{{Key: args[0], Value: value, Source: "config"}})
+			}
+			fmt.Printf("%s = %s\n", color.CyanString(args[0]), value)
+			return nil
+		},
+	}
+}
+
+func newConfigUnsetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unset <key>",
+		Short: "Remove a setting from the config file so it uses its default",
+		Args:  cobra.ExactArgs(1),
+		Annotations: map[string]string{
+			allowInvalidConfig: "true",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			doc, err := config.OpenDocument(paths.ConfigFile)
+			if err != nil {
+				return err
+			}
+			removed, err := doc.Unset(args[0])
+			if err != nil {
+				return err
+			}
+			if !removed && !machineOutput() {
+				fmt.Printf("%s is not set in %s\n", color.CyanString(args[0]), doc.Path())
+				return nil
+			}
+			if removed {
+				if err := doc.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %v", err)
+				}
+			}
+
+			// Map entries, such as a budget, have no default and are gone
+			value, err := config.Default().Get(args[0])
+			var unknown *config.ErrUnknownKey
+			if errors.As(err, &unknown) {
+				if machineOutput() {
+					return writeOutput(settingsOutput{})
+				}
+				fmt.Printf("%s removed from %s\n", color.CyanString(args[0]), doc.Path())
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if machineOutput() {
+				return writeOutput(settingsOutput{{Key: args[0], Value: value, Source: "default"}})
+			}
+			fmt.Printf("%s = %s (default)\n", color.CyanString(args[0]), value)
+			return nil
+		},
+	}
+}
+
+func newConfigEditCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit",
+		Short: "Open the config file in $VISUAL or $EDITOR",
+		Args:  cobra.NoArgs,
+		Annotations: map[string]string{
+			allowInvalidConfig: "true",
+			textOutputOnly:     "true",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := paths.ConfigFile
+
+			editor := strings.Fields(os.Getenv("VISUAL"))
+			if len(editor) == 0 {
+				editor = strings.Fields(os.Getenv("EDITOR"))
+			}
+			if len(editor) == 0 {
+				editor = []string{"vi"}
+			}
+
+			edit := exec.Command(editor[0], append(editor[1:], path)...)
+			edit.Stdin, edit.Stdout, edit.Stderr = os.Stdin, os.Stdout, os.Stderr
+			if err := edit.Run(); err != nil {
+				return fmt.Errorf("failed to run editor: %v", err)
+			}
+
+			if _, err := loadConfig(path); err != nil {
+				return err
+			}
+			fmt.Println(color.GreenString("Configuration is valid"))
+			return nil
+		},
+	}
+}
+
+func newConfigPathCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "path",
+		Short: "Print the path of the config file",
+		Args:  cobra.NoArgs,
+		Annotations: map[string]string{
+			allowInvalidConfig: "true",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := paths.ConfigFile
+			if machineOutput() {
+				return writeOutput(configPathOutput{Path: path})
+			}
+			fmt.Println(path)
+			return nil
+		},
+	}
+}
+
+func newConfigResetCmd() *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "reset",
+		Short: "Replace the config file with the default settings",
+		Args:  cobra.NoArgs,
+		Annotations: map[string]string{
+			allowInvalidConfig: "true",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := paths.ConfigFile
+
+			if !yes && machineOutput() {
+				return fmt.Errorf("--yes is required with --output %s", outputFlag)
+			}
+			if !yes {
+				fmt.Printf("Replace %s with the default settings? [y/N] ", path)
+				answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+				if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+					fmt.Println("Aborted")
+					return nil
+				}
+			}
+
+			if err := config.Default().Save(path); err != nil {
+				return fmt.Errorf("failed to save config: %v", err)
+			}
+			if machineOutput() {
+				return writeOutput(configPathOutput{Path: path})
+			}
+			fmt.Println("Configuration reset to defaults")
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Do not ask for confirmation")
+
+	return cmd
+}
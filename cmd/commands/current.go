@@ -43,6 +43,13 @@ func (c *currentCmd) run(cmd *cobra.Command, args []string) error {
 	}
 
 	session, err := sm.GetLast()
+	if machineOutput() {
+		// Scripts get a single snapshot of the running session, or null
+		if err != nil || session == nil || !session.IsActive() {
+			session = nil
+		}
+		return writeOutput(newSingleSessionOutput(session, time.Now()))
+	}
 	if err != nil {
 		fmt.Println("No sessions found. Start a session with 'gotrack start <task>'.")
 		return nil
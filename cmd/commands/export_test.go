@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/storage"
+	pkgPomodoro "github.com/AndriyBarskyi/gotrack/internal/tracker/pomodoro"
+)
+
+// Output formats and schema constructors for the golden tests
+var (
+	OutputFormats           = outputFormats[1:]
+	WriteOutputAs           = writeOutputAs
+	NewSessionListOutput    = newSessionListOutput
+	NewSingleSessionOutput  = newSingleSessionOutput
+	NewStatsOutput          = newStatsOutput
+	NewReportOutput         = newReportOutput
+	NewPomodoroStatusOutput = newPomodoroStatusOutput
+	NewHeatmapOutput        = newHeatmapOutput
+	NewProfileOutput        = newProfileOutput
+	NewTrendsOutput         = newTrendsOutput
+	NewFocusOutput          = newFocusOutput
+	NewBudgetsOutput        = newBudgetsOutput
+	NewScoreOutput          = newScoreOutput
+	NewTimesheetOutput      = newTimesheetOutput
+	NewStandupOutput        = newStandupOutput
+)
+
+// Markdown and text writers for the golden tests
+var (
+	WriteTimesheetMarkdown = writeTimesheetMarkdown
+	WriteStandup           = writeStandup
+)
+
+// RestartIfVoided restarts the work phase of p when a recorded interruption
+// voided it, reading the interruptions from is
+func RestartIfVoided(p *pkgPomodoro.Pomodoro, is *storage.InterruptionStorage) *models.Interruption {
+	defer func(saved *storage.InterruptionStorage) { interruptionStorage = saved }(interruptionStorage)
+	interruptionStorage = is
+	return restartIfVoided(p, &voidWatch{})
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/AndriyBarskyi/gotrack/internal/tracker"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
+)
+
+// heatmapThresholds are the upper bounds of the time spent on a day for each
+// shade of the heatmap but the darkest one
+var heatmapThresholds = []time.Duration{time.Hour, 2 * time.Hour, 4 * time.Hour}
+
+// heatmapBlocks are the cells of days without tracked time and of each shade
+var heatmapBlocks = []string{"·", "░", "▒", "▓", "█"}
+
+type heatmapCmd struct {
+	sessionManager *tracker.SessionManager
+	task           string
+	year           int
+}
+
+// NewHeatmapCmd creates a new heatmap command
+func NewHeatmapCmd(sm *tracker.SessionManager) *cobra.Command {
+	c := &heatmapCmd{
+		sessionManager: sm,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "heatmap",
+		Short: "Show a calendar heatmap of the time tracked per day",
+		Long: `Show a calendar of the last year, or of the year given with --year, with one
+cell per day shaded by the time tracked on it. The days of the longest streak
+of tracked days are highlighted.`,
+		Example: `
+  gotrack heatmap
+  gotrack heatmap --task <task name>
+  gotrack heatmap --year 2025
+`,
+		Args: cobra.NoArgs,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringVar(&c.task, "task", "", "Only count sessions of a task")
+	cmd.Flags().IntVar(&c.year, "year", 0, "Show a calendar year instead of the last 12 months")
+
+	return cmd
+}
+
+func (c *heatmapCmd) run(cmd *cobra.Command, args []string) error {
+	sm := c.sessionManager
+	if sm == nil {
+		sm = GetSessionManager()
+		if sm == nil {
+			fmt.Println("No session manager available. Please ensure GoTrack is properly initialized.")
+			return fmt.Errorf("session manager not initialized")
+		}
+	}
+
+	days := reportDays()
+	now := time.Now()
+	from, to := c.dateRange(days, now)
+	if !from.Before(to) {
+		return fmt.Errorf("year %d has not started yet", c.year)
+	}
+
+	ssns, err := sm.GetAllSessions()
+	if err != nil {
+		return fmt.Errorf("failed to get sessions: %v", err)
+	}
+
+	heatmap := analytics.BuildHeatmap(ssns, c.task, days, from, to, now)
+	if machineOutput() {
+		return writeOutput(newHeatmapOutput(heatmap, days))
+	}
+	return c.write(os.Stdout, heatmap)
+}
+
+// dateRange returns the days shown: the calendar year given with --year, or
+// the 52 weeks before the current one, both up to today at most
+func (c *heatmapCmd) dateRange(days analytics.Days, now time.Time) (from, to time.Time) {
+	to = days.Next(now)
+	if c.year == 0 {
+		return days.WeekStart(now.AddDate(0, 0, -52*7)), to
+	}
+
+	from, end := days.Year(c.year)
+	if end.Before(to) {
+		to = end
+	}
+	return from, to
+}
+
+func (c *heatmapCmd) write(out io.Writer, h *analytics.Heatmap) error {
+	title := fmt.Sprintf("%s to %s", h.From.Format("Jan 2, 2006"), h.Days[len(h.Days)-1].Day.Format("Jan 2, 2006"))
+	if c.year != 0 {
+		title = fmt.Sprint(c.year)
+	}
+	if c.task != "" {
+		title += " - " + c.task
+	}
+	fmt.Fprintln(out, color.New(color.Bold).Sprint(title))
+	fmt.Fprintln(out)
+
+	// One column per week, one row per weekday
+	var weeks [][7]*analytics.HeatmapDay
+	for i := range h.Days {
+		day := &h.Days[i]
+		weekday := day.Day.Weekday()
+		if len(weeks) == 0 || weekday == time.Sunday {
+			weeks = append(weeks, [7]*analytics.HeatmapDay{})
+		}
+		weeks[len(weeks)-1][weekday] = day
+	}
+
+	// Weeks are labelled with the month of their first day when it changes
+	const labelWidth = 4
+	var labels []int
+	var month time.Month
+	for col, week := range weeks {
+		first := week[0]
+		for i := 1; first == nil; i++ {
+			first = week[i]
+		}
+		if first.Day.Month() != month {
+			labels = append(labels, col)
+			month = first.Day.Month()
+		}
+	}
+	if len(labels) > 1 && labels[1] < 2 {
+		labels = labels[1:]
+	}
+	months := []rune(strings.Repeat(" ", labelWidth+2*len(weeks)+3))
+	for _, col := range labels {
+		week := weeks[col]
+		for _, day := range week {
+			if day != nil {
+				copy(months[labelWidth+2*col:], []rune(day.Day.Format("Jan")))
+				break
+			}
+		}
+	}
+	fmt.Fprintln(out, strings.TrimRight(string(months), " "))
+
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		label := ""
+		if weekday%2 == 1 {
+			label = weekday.String()[:3]
+		}
+		var row strings.Builder
+		fmt.Fprintf(&row, "%-*s", labelWidth, label)
+		for _, week := range weeks {
+			row.WriteString(heatmapCell(week[weekday]) + " ")
+		}
+		fmt.Fprintln(out, strings.TrimRight(row.String(), " "))
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, heatmapLegend())
+	fmt.Fprintf(out, "\nTracked days: %d\n", h.Tracked())
+	fmt.Fprintf(out, "Total duration: %s\n", formatDuration(h.Total()))
+	if h.Streak > 0 {
+		var first, last time.Time
+		for _, day := range h.Days {
+			if day.Streak {
+				if first.IsZero() {
+					first = day.Day
+				}
+				last = day.Day
+			}
+		}
+		fmt.Fprintf(out, "Longest streak: %s (%s - %s)\n",
+			color.YellowString("%d days", h.Streak), first.Format("Jan 2"), last.Format("Jan 2"))
+	}
+	return nil
+}
+
+// heatmapLevel returns the shade of a day, 0 for days without tracked time
+func heatmapLevel(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	for i, threshold := range heatmapThresholds {
+		if d < threshold {
+			return i + 1
+		}
+	}
+	return len(heatmapThresholds) + 1
+}
+
+func heatmapCell(day *analytics.HeatmapDay) string {
+	if day == nil {
+		return " "
+	}
+	level := heatmapLevel(day.Total)
+	block := heatmapBlocks[level]
+	switch {
+	case level == 0:
+		return color.HiBlackString(block)
+	case day.Streak:
+		return color.YellowString(block)
+	case level == len(heatmapBlocks)-1:
+		return color.HiGreenString(block)
+	default:
+		return color.GreenString(block)
+	}
+}
+
+func heatmapLegend() string {
+	parts := []string{color.HiBlackString(heatmapBlocks[0]) + " none"}
+	var lower time.Duration
+	for i, threshold := range heatmapThresholds {
+		label := fmt.Sprintf("under %s", formatHours(threshold))
+		if lower > 0 {
+			label = fmt.Sprintf("%s-%s", formatHours(lower), formatHours(threshold))
+		}
+		parts = append(parts, color.GreenString(heatmapBlocks[i+1])+" "+label)
+		lower = threshold
+	}
+	parts = append(parts,
+		color.HiGreenString(heatmapBlocks[len(heatmapBlocks)-1])+" "+formatHours(lower)+" or more",
+		color.YellowString(heatmapBlocks[len(heatmapBlocks)-1])+" longest streak",
+	)
+	return strings.Join(parts, "  ")
+}
+
+func formatHours(d time.Duration) string {
+	return fmt.Sprintf("%gh", d.Hours())
+}
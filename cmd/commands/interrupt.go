@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	pkgPomodoro "github.com/AndriyBarskyi/gotrack/internal/tracker/pomodoro"
+)
+
+type interruptCmd struct {
+	external bool
+	void     bool
+}
+
+// NewInterruptCmd creates a new pomodoro interrupt command
+func NewInterruptCmd() *cobra.Command {
+	c := &interruptCmd{}
+
+	cmd := &cobra.Command{
+		Use:   "interrupt [reason]",
+		Short: "Record an interruption of the running Pomodoro",
+		Long: `Record an interruption against the work interval of the Pomodoro that is
+running in another terminal.
+
+Interruptions are internal (you got distracted) unless --external is given
+(someone else interrupted you). With --void the work interval is abandoned
+and started over, so it does not count as a completed Pomodoro.`,
+		Example: `
+  gotrack pomo interrupt "Checked email"
+  gotrack pomo interrupt --external "Phone call"
+  gotrack pomo interrupt --external --void "Production incident"
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: c.run,
+	}
+
+	cmd.Flags().BoolVarP(&c.external, "external", "e", false, "The interruption was caused by someone else")
+	cmd.Flags().BoolVar(&c.void, "void", false, "Void the work interval and restart it")
+
+	return cmd
+}
+
+func (c *interruptCmd) run(cmd *cobra.Command, args []string) error {
+	if interruptionStorage == nil {
+		return fmt.Errorf("interruption storage not initialized")
+	}
+
+	status, err := pkgPomodoro.ReadStatus(pomodoroStatusPath)
+	if err != nil {
+		if errors.Is(err, pkgPomodoro.ErrNoStatus) {
+			return fmt.Errorf("no Pomodoro is running; start one with 'gotrack pomo <task>', or with 'gotrack pomo -- interrupt' for a task named interrupt")
+		}
+		return err
+	}
+	if !status.IsWorking() {
+		return fmt.Errorf("the Pomodoro is not in a work interval (currently %s)", status.State)
+	}
+
+	interruption := &models.Interruption{
+		Task:          status.Task,
+		IntervalStart: status.PhaseStart,
+		Time:          time.Now(),
+		External:      c.external,
+		Void:          c.void,
+	}
+	if len(args) > 0 {
+		interruption.Reason = args[0]
+	}
+
+	if err := recordInterruption(interruption); err != nil {
+		return err
+	}
+
+	if c.void && !machineOutput() {
+		fmt.Println("The work interval will be restarted.")
+	}
+	return nil
+}
+
+// recordInterruption saves the interruption and reports it to the user
+func recordInterruption(interruption *models.Interruption) error {
+	if err := interruptionStorage.Save(interruption); err != nil {
+		return fmt.Errorf("failed to record interruption: %v", err)
+	}
+	if machineOutput() {
+		return writeOutput(newInterruptionOutput(interruption))
+	}
+
+	fmt.Printf("Recorded %s interruption of %s\n",
+		interruption.Kind(),
+		color.CyanString(interruption.Task),
+	)
+	return nil
+}
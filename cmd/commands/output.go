@@ -0,0 +1,1099 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/AndriyBarskyi/gotrack/internal/config"
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
+	pkgPomodoro "github.com/AndriyBarskyi/gotrack/internal/tracker/pomodoro"
+)
+
+// Formats accepted by --output. Text is for people and may change between
+// versions, the other formats follow the schemas below and stay stable.
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+	outputCSV  = "csv"
+)
+
+var outputFormats = []string{outputText, outputJSON, outputYAML, outputCSV}
+
+// textOutputOnly is the annotation of interactive commands that have no
+// machine-readable output
+const textOutputOnly = "text-output-only"
+
+// checkOutputFormat validates --output for the command about to run
+func checkOutputFormat(cmd *cobra.Command) error {
+	if !slices.Contains(outputFormats, outputFlag) {
+		return fmt.Errorf("unknown output format %q, expected one of %s", outputFlag, strings.Join(outputFormats, ", "))
+	}
+	if machineOutput() && cmd.Annotations[textOutputOnly] != "" {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("'%s' is interactive and only supports --output text", cmd.CommandPath())
+	}
+	return nil
+}
+
+// machineOutput reports whether a machine-readable format was selected, in
+// which case commands write their result with writeOutput instead of
+// printing text
+func machineOutput() bool {
+	return outputFlag != "" && outputFlag != outputText
+}
+
+// table is implemented by output values to be written as CSV
+type table interface {
+	csvHeader() []string
+	csvRows() [][]string
+}
+
+// writeOutput writes v to stdout in the format selected with --output
+func writeOutput(v table) error {
+	return writeOutputAs(os.Stdout, outputFlag, v)
+}
+
+// writeOutputAs writes v to w in the given machine-readable format
+func writeOutputAs(w io.Writer, format string, v table) error {
+	switch format {
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case outputYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	case outputCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(v.csvHeader()); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(v.csvRows()); err != nil {
+			return err
+		}
+		return cw.Error()
+	default:
+		return fmt.Errorf("output format %q is not machine-readable", format)
+	}
+}
+
+// seconds returns a duration in whole seconds, the unit of every duration in
+// machine-readable output
+func seconds(d time.Duration) int64 {
+	return int64(d.Round(time.Second) / time.Second)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// sessionOutput is the schema of a session
+type sessionOutput struct {
+	Task      string     `json:"task" yaml:"task"`
+	Project   string     `json:"project" yaml:"project"`
+	Tags      []string   `json:"tags" yaml:"tags"`
+	StartTime time.Time  `json:"start_time" yaml:"start_time"`
+	EndTime   *time.Time `json:"end_time" yaml:"end_time"`
+	// Duration is up to now for the running session
+	Duration int64  `json:"duration_seconds" yaml:"duration_seconds"`
+	Running  bool   `json:"running" yaml:"running"`
+	Note     string `json:"note" yaml:"note"`
+}
+
+func newSessionOutput(ssn models.Session, now time.Time) sessionOutput {
+	out := sessionOutput{
+		Task:      ssn.Task,
+		Project:   ssn.Project,
+		Tags:      ssn.Tags,
+		StartTime: ssn.StartTime,
+		Running:   ssn.IsActive(),
+		Note:      ssn.Note,
+	}
+	if out.Tags == nil {
+		out.Tags = []string{}
+	}
+	end := now
+	if !ssn.EndTime.IsZero() {
+		out.EndTime = &ssn.EndTime
+		end = ssn.EndTime
+	}
+	out.Duration = seconds(end.Sub(ssn.StartTime))
+	return out
+}
+
+var sessionCSVHeader = []string{"task", "project", "tags", "start_time", "end_time", "duration_seconds", "running", "note"}
+
+func (s sessionOutput) csvRow() []string {
+	end := ""
+	if s.EndTime != nil {
+		end = formatTime(*s.EndTime)
+	}
+	return []string{
+		s.Task,
+		s.Project,
+		strings.Join(s.Tags, ";"),
+		formatTime(s.StartTime),
+		end,
+		strconv.FormatInt(s.Duration, 10),
+		strconv.FormatBool(s.Running),
+		s.Note,
+	}
+}
+
+// sessionListOutput is the schema of a list of sessions
+type sessionListOutput []sessionOutput
+
+func newSessionListOutput(ssns []models.Session, now time.Time) sessionListOutput {
+	out := make(sessionListOutput, len(ssns))
+	for i, ssn := range ssns {
+		out[i] = newSessionOutput(ssn, now)
+	}
+	return out
+}
+
+func (l sessionListOutput) csvHeader() []string { return sessionCSVHeader }
+
+func (l sessionListOutput) csvRows() [][]string {
+	rows := make([][]string, len(l))
+	for i, s := range l {
+		rows[i] = s.csvRow()
+	}
+	return rows
+}
+
+// singleSessionOutput is the schema of commands about one session, null when
+// there is none
+type singleSessionOutput struct {
+	*sessionOutput
+}
+
+func newSingleSessionOutput(ssn *models.Session, now time.Time) singleSessionOutput {
+	if ssn == nil {
+		return singleSessionOutput{}
+	}
+	out := newSessionOutput(*ssn, now)
+	return singleSessionOutput{&out}
+}
+
+// MarshalJSON implements json.Marshaler
+func (s singleSessionOutput) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.sessionOutput)
+}
+
+// MarshalYAML implements yaml.Marshaler
+func (s singleSessionOutput) MarshalYAML() (any, error) {
+	return s.sessionOutput, nil
+}
+
+func (s singleSessionOutput) csvHeader() []string { return sessionCSVHeader }
+
+func (s singleSessionOutput) csvRows() [][]string {
+	if s.sessionOutput == nil {
+		return nil
+	}
+	return [][]string{s.csvRow()}
+}
+
+// statsOutput is the schema of the statistics of show
+type statsOutput struct {
+	Sessions sessionListOutput `json:"sessions" yaml:"sessions"`
+	Stats    durationStats     `json:"stats" yaml:"stats"`
+	Pomodoro pomodoroStats     `json:"pomodoros" yaml:"pomodoros"`
+}
+
+type durationStats struct {
+	Today             int64             `json:"today_seconds" yaml:"today_seconds"`
+	Week              int64             `json:"week_seconds" yaml:"week_seconds"`
+	Month             int64             `json:"month_seconds" yaml:"month_seconds"`
+	Year              int64             `json:"year_seconds" yaml:"year_seconds"`
+	Total             int64             `json:"total_seconds" yaml:"total_seconds"`
+	Running           int64             `json:"running_seconds" yaml:"running_seconds"`
+	ConsecutiveDays   int               `json:"consecutive_days" yaml:"consecutive_days"`
+	LongestStreak     int               `json:"longest_streak" yaml:"longest_streak"`
+	ProductivityScore float64           `json:"productivity_score" yaml:"productivity_score"`
+	TopTasks          []taskStatsOutput `json:"top_tasks" yaml:"top_tasks"`
+}
+
+type taskStatsOutput struct {
+	Task     string `json:"task" yaml:"task"`
+	Duration int64  `json:"duration_seconds" yaml:"duration_seconds"`
+}
+
+type pomodoroStats struct {
+	Today                     int     `json:"today" yaml:"today"`
+	DailyGoal                 int     `json:"daily_goal" yaml:"daily_goal"`
+	GoalStreak                int     `json:"goal_streak" yaml:"goal_streak"`
+	LongestGoalStreak         int     `json:"longest_goal_streak" yaml:"longest_goal_streak"`
+	CompletionRate            float64 `json:"completion_rate" yaml:"completion_rate"`
+	AverageBeforeInterruption float64 `json:"average_before_interruption" yaml:"average_before_interruption"`
+}
+
+// newStatsOutput computes the statistics of show over sessions, pomodoros and
+// interruptions, for one task or for all of them when task is empty
+func newStatsOutput(ssns []models.Session, poms []models.Pomodoro, ints []models.Interruption, task string, dailyGoal int, score analytics.ScoreSettings, days analytics.Days, now time.Time) statsOutput {
+	total := analytics.CalculateTotalDuration(ssns, task)
+	out := statsOutput{
+		Sessions: newSessionListOutput(ssns, now),
+		Stats: durationStats{
+			Today:             seconds(analytics.CalculateTodayDuration(ssns, task, days)),
+			Week:              seconds(analytics.CalculateWeeklyDuration(ssns, task, days)),
+			Month:             seconds(analytics.CalculateMonthlyDuration(ssns, task, days)),
+			Year:              seconds(analytics.CalculateYearlyDuration(ssns, task, days)),
+			Total:             seconds(total),
+			Running:           seconds(total - analytics.CalculateTotalDuration(analytics.Finished(ssns), task)),
+			ConsecutiveDays:   analytics.CalculateConsecutiveDays(ssns, days),
+			LongestStreak:     analytics.CalculateLongestStreak(ssns, days),
+			ProductivityScore: analytics.CalculateScore(ssns, score, days, now).Total,
+			TopTasks:          []taskStatsOutput{},
+		},
+		Pomodoro: pomodoroStats{
+			DailyGoal:                 dailyGoal,
+			CompletionRate:            analytics.PomodoroCompletionRate(poms, task),
+			AverageBeforeInterruption: analytics.AveragePomodorosBeforeInterruption(poms, ints, task, days),
+		},
+	}
+	for _, t := range analytics.GetTopTasks(ssns, 5) {
+		out.Stats.TopTasks = append(out.Stats.TopTasks, taskStatsOutput{Task: t.Task, Duration: seconds(t.Duration)})
+	}
+
+	perDay := analytics.PomodorosPerDay(poms, task, days)
+	if n := len(perDay); n > 0 && perDay[n-1].Day == days.Key(now) {
+		out.Pomodoro.Today = perDay[n-1].Completed
+	}
+	out.Pomodoro.GoalStreak, out.Pomodoro.LongestGoalStreak = analytics.PomodoroGoalStreaks(perDay, dailyGoal, days, now)
+	return out
+}
+
+// csvHeader implements table. Statistics are written as one metric per row,
+// the sessions are left out.
+func (s statsOutput) csvHeader() []string { return []string{"metric", "value"} }
+
+func (s statsOutput) csvRows() [][]string {
+	st, p := s.Stats, s.Pomodoro
+	itoa := func(i int64) string { return strconv.FormatInt(i, 10) }
+	ftoa := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+	rows := [][]string{
+		{"today_seconds", itoa(st.Today)},
+		{"week_seconds", itoa(st.Week)},
+		{"month_seconds", itoa(st.Month)},
+		{"year_seconds", itoa(st.Year)},
+		{"total_seconds", itoa(st.Total)},
+		{"running_seconds", itoa(st.Running)},
+		{"consecutive_days", strconv.Itoa(st.ConsecutiveDays)},
+		{"longest_streak", strconv.Itoa(st.LongestStreak)},
+		{"productivity_score", ftoa(st.ProductivityScore)},
+		{"pomodoros.today", strconv.Itoa(p.Today)},
+		{"pomodoros.daily_goal", strconv.Itoa(p.DailyGoal)},
+		{"pomodoros.goal_streak", strconv.Itoa(p.GoalStreak)},
+		{"pomodoros.longest_goal_streak", strconv.Itoa(p.LongestGoalStreak)},
+		{"pomodoros.completion_rate", ftoa(p.CompletionRate)},
+		{"pomodoros.average_before_interruption", ftoa(p.AverageBeforeInterruption)},
+	}
+	for _, t := range st.TopTasks {
+		rows = append(rows, []string{"top_tasks." + t.Task, itoa(t.Duration)})
+	}
+	return rows
+}
+
+// reportOutput is the schema of report
+type reportOutput struct {
+	GroupBy []string          `json:"group_by" yaml:"group_by"`
+	Rows    []reportRowOutput `json:"rows" yaml:"rows"`
+	Total   reportRowOutput   `json:"total" yaml:"total"`
+
+	percentiles []float64
+}
+
+type reportRowOutput struct {
+	// Keys maps each dimension of group_by to the value of the row
+	Keys        map[string]string `json:"keys" yaml:"keys"`
+	Sessions    int               `json:"sessions" yaml:"sessions"`
+	Total       int64             `json:"total_seconds" yaml:"total_seconds"`
+	Average     int64             `json:"average_seconds" yaml:"average_seconds"`
+	Percentiles map[string]int64  `json:"percentile_seconds" yaml:"percentile_seconds"`
+}
+
+func newReportOutput(r *analytics.Report, percentiles []float64) reportOutput {
+	out := reportOutput{
+		GroupBy:     make([]string, len(r.GroupBy)),
+		Rows:        make([]reportRowOutput, len(r.Rows)),
+		percentiles: percentiles,
+	}
+	for i, g := range r.GroupBy {
+		out.GroupBy[i] = string(g)
+	}
+	row := func(keys []string, row analytics.Row) reportRowOutput {
+		o := reportRowOutput{
+			Keys:        make(map[string]string, len(keys)),
+			Sessions:    row.Count,
+			Total:       seconds(row.Total),
+			Average:     seconds(row.Average()),
+			Percentiles: make(map[string]int64, len(percentiles)),
+		}
+		for i, k := range keys {
+			o.Keys[out.GroupBy[i]] = k
+		}
+		for _, p := range percentiles {
+			o.Percentiles[percentileName(p)] = seconds(row.Percentile(p))
+		}
+		return o
+	}
+	for i, r := range r.Rows {
+		out.Rows[i] = row(r.Keys, r)
+	}
+	out.Total = row(nil, r.Total)
+	return out
+}
+
+func percentileName(p float64) string {
+	return "p" + strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+// csvHeader implements table. Rows are written without the total, except
+// for reports without dimensions that only have a total.
+func (r reportOutput) csvHeader() []string {
+	header := append(slices.Clone(r.GroupBy), "sessions", "total_seconds", "average_seconds")
+	for _, p := range r.percentiles {
+		header = append(header, percentileName(p)+"_seconds")
+	}
+	return header
+}
+
+func (r reportOutput) csvRows() [][]string {
+	rows := r.Rows
+	if len(r.GroupBy) == 0 {
+		rows = []reportRowOutput{r.Total}
+	}
+	records := make([][]string, len(rows))
+	for i, row := range rows {
+		var record []string
+		for _, g := range r.GroupBy {
+			record = append(record, row.Keys[g])
+		}
+		record = append(record,
+			strconv.Itoa(row.Sessions),
+			strconv.FormatInt(row.Total, 10),
+			strconv.FormatInt(row.Average, 10),
+		)
+		for _, p := range r.percentiles {
+			record = append(record, strconv.FormatInt(row.Percentiles[percentileName(p)], 10))
+		}
+		records[i] = record
+	}
+	return records
+}
+
+// heatmapOutput is the schema of heatmap
+type heatmapOutput struct {
+	// From and To are the first and the last day shown
+	From          string             `json:"from" yaml:"from"`
+	To            string             `json:"to" yaml:"to"`
+	LongestStreak int                `json:"longest_streak" yaml:"longest_streak"`
+	Days          []heatmapDayOutput `json:"days" yaml:"days"`
+}
+
+type heatmapDayOutput struct {
+	Day    string `json:"day" yaml:"day"`
+	Total  int64  `json:"total_seconds" yaml:"total_seconds"`
+	Streak bool   `json:"streak" yaml:"streak"`
+}
+
+func newHeatmapOutput(h *analytics.Heatmap, days analytics.Days) heatmapOutput {
+	out := heatmapOutput{
+		LongestStreak: h.Streak,
+		Days:          make([]heatmapDayOutput, len(h.Days)),
+	}
+	for i, d := range h.Days {
+		out.Days[i] = heatmapDayOutput{Day: days.Key(d.Day), Total: seconds(d.Total), Streak: d.Streak}
+	}
+	if len(out.Days) > 0 {
+		out.From, out.To = out.Days[0].Day, out.Days[len(out.Days)-1].Day
+	}
+	return out
+}
+
+func (h heatmapOutput) csvHeader() []string { return []string{"day", "total_seconds", "streak"} }
+
+func (h heatmapOutput) csvRows() [][]string {
+	rows := make([][]string, len(h.Days))
+	for i, d := range h.Days {
+		rows[i] = []string{d.Day, strconv.FormatInt(d.Total, 10), strconv.FormatBool(d.Streak)}
+	}
+	return rows
+}
+
+// profileOutput is the schema of report --profile
+type profileOutput struct {
+	Hours    []profileBucketOutput `json:"hours" yaml:"hours"`
+	Weekdays []profileBucketOutput `json:"weekdays" yaml:"weekdays"`
+	Total    int64                 `json:"total_seconds" yaml:"total_seconds"`
+}
+
+type profileBucketOutput struct {
+	// Key is the hour, e.g. "09:00", or the day of the week, e.g. "Monday"
+	Key   string  `json:"key" yaml:"key"`
+	Total int64   `json:"total_seconds" yaml:"total_seconds"`
+	Share float64 `json:"share" yaml:"share"`
+}
+
+func newProfileOutput(p *analytics.Profile) profileOutput {
+	out := profileOutput{Total: seconds(p.Total)}
+	bucket := func(key string, d time.Duration) profileBucketOutput {
+		return profileBucketOutput{Key: key, Total: seconds(d), Share: p.Share(d)}
+	}
+	for h, d := range p.Hours {
+		out.Hours = append(out.Hours, bucket(fmt.Sprintf("%02d:00", h), d))
+	}
+	for wd, d := range p.Weekdays {
+		out.Weekdays = append(out.Weekdays, bucket(time.Weekday(wd).String(), d))
+	}
+	return out
+}
+
+// csvHeader implements table. Hours come first, then the days of the week.
+func (p profileOutput) csvHeader() []string {
+	return []string{"dimension", "key", "total_seconds", "share"}
+}
+
+func (p profileOutput) csvRows() [][]string {
+	var rows [][]string
+	add := func(dimension string, buckets []profileBucketOutput) {
+		for _, b := range buckets {
+			rows = append(rows, []string{dimension, b.Key, strconv.FormatInt(b.Total, 10), strconv.FormatFloat(b.Share, 'f', -1, 64)})
+		}
+	}
+	add(string(analytics.GroupHour), p.Hours)
+	add(string(analytics.GroupWeekday), p.Weekdays)
+	return rows
+}
+
+// focusOutput is the schema of report --focus
+type focusOutput struct {
+	Sessions       int              `json:"sessions" yaml:"sessions"`
+	Switches       int              `json:"switches" yaml:"switches"`
+	SwitchesPerDay float64          `json:"switches_per_day" yaml:"switches_per_day"`
+	Total          int64            `json:"total_seconds" yaml:"total_seconds"`
+	AverageSession int64            `json:"average_session_seconds" yaml:"average_session_seconds"`
+	Longest        focusBlockOutput `json:"longest_block" yaml:"longest_block"`
+	Fragmentation  float64          `json:"fragmentation" yaml:"fragmentation"`
+	Days           []focusDayOutput `json:"days" yaml:"days"`
+}
+
+type focusBlockOutput struct {
+	Task     string     `json:"task" yaml:"task"`
+	Start    *time.Time `json:"start" yaml:"start"`
+	Duration int64      `json:"duration_seconds" yaml:"duration_seconds"`
+}
+
+type focusDayOutput struct {
+	Day            string  `json:"day" yaml:"day"`
+	Sessions       int     `json:"sessions" yaml:"sessions"`
+	Switches       int     `json:"switches" yaml:"switches"`
+	Total          int64   `json:"total_seconds" yaml:"total_seconds"`
+	AverageSession int64   `json:"average_session_seconds" yaml:"average_session_seconds"`
+	Longest        int64   `json:"longest_block_seconds" yaml:"longest_block_seconds"`
+	Fragmentation  float64 `json:"fragmentation" yaml:"fragmentation"`
+}
+
+func newFocusBlockOutput(b analytics.FocusBlock) focusBlockOutput {
+	out := focusBlockOutput{Task: b.Task, Duration: seconds(b.Duration)}
+	if !b.Start.IsZero() {
+		out.Start = &b.Start
+	}
+	return out
+}
+
+func newFocusOutput(f *analytics.Focus, days analytics.Days) focusOutput {
+	out := focusOutput{
+		Sessions:       f.Sessions,
+		Switches:       f.Switches,
+		SwitchesPerDay: f.SwitchesPerDay(),
+		Total:          seconds(f.Total),
+		AverageSession: seconds(f.AverageSession()),
+		Longest:        newFocusBlockOutput(f.Longest),
+		Fragmentation:  f.Fragmentation,
+		Days:           make([]focusDayOutput, len(f.Days)),
+	}
+	for i, d := range f.Days {
+		out.Days[i] = focusDayOutput{
+			Day:            days.Key(d.Day),
+			Sessions:       d.Sessions,
+			Switches:       d.Switches,
+			Total:          seconds(d.Total),
+			AverageSession: seconds(d.AverageSession()),
+			Longest:        seconds(d.Longest.Duration),
+			Fragmentation:  d.Fragmentation,
+		}
+	}
+	return out
+}
+
+// csvHeader implements table with one row per day
+func (f focusOutput) csvHeader() []string {
+	return []string{"day", "sessions", "switches", "total_seconds", "average_session_seconds", "longest_block_seconds", "fragmentation"}
+}
+
+func (f focusOutput) csvRows() [][]string {
+	rows := make([][]string, len(f.Days))
+	for i, d := range f.Days {
+		rows[i] = []string{
+			d.Day,
+			strconv.Itoa(d.Sessions),
+			strconv.Itoa(d.Switches),
+			strconv.FormatInt(d.Total, 10),
+			strconv.FormatInt(d.AverageSession, 10),
+			strconv.FormatInt(d.Longest, 10),
+			strconv.FormatFloat(d.Fragmentation, 'f', -1, 64),
+		}
+	}
+	return rows
+}
+
+// trendsOutput is the schema of report --compare
+type trendsOutput []trendOutput
+
+type trendOutput struct {
+	Name    string       `json:"name" yaml:"name"`
+	Current periodOutput `json:"current" yaml:"current"`
+	// Previous lists the periods whose average the current one is compared
+	// with
+	Previous []periodOutput     `json:"previous" yaml:"previous"`
+	Total    changeOutput       `json:"total" yaml:"total"`
+	Tasks    []taskChangeOutput `json:"tasks" yaml:"tasks"`
+}
+
+type periodOutput struct {
+	From time.Time `json:"from" yaml:"from"`
+	To   time.Time `json:"to" yaml:"to"`
+}
+
+type changeOutput struct {
+	Current  int64 `json:"current_seconds" yaml:"current_seconds"`
+	Previous int64 `json:"previous_seconds" yaml:"previous_seconds"`
+	Delta    int64 `json:"delta_seconds" yaml:"delta_seconds"`
+	// Percent is null when nothing was spent in the previous periods
+	Percent *float64 `json:"percent" yaml:"percent"`
+}
+
+type taskChangeOutput struct {
+	Task         string `json:"task" yaml:"task"`
+	changeOutput `yaml:",inline"`
+}
+
+func newChangeOutput(c analytics.Change) changeOutput {
+	out := changeOutput{
+		Current:  seconds(c.Current),
+		Previous: seconds(c.Previous),
+		Delta:    seconds(c.Delta()),
+	}
+	if percent, ok := c.Percent(); ok {
+		out.Percent = &percent
+	}
+	return out
+}
+
+func newTrendsOutput(trends []analytics.Trend) trendsOutput {
+	out := make(trendsOutput, len(trends))
+	for i, t := range trends {
+		o := trendOutput{
+			Name:    t.Name,
+			Current: periodOutput{t.Current.From, t.Current.To},
+			Total:   newChangeOutput(t.Total),
+			Tasks:   make([]taskChangeOutput, len(t.Tasks)),
+		}
+		for _, p := range t.Previous {
+			o.Previous = append(o.Previous, periodOutput{p.From, p.To})
+		}
+		for j, tc := range t.Tasks {
+			o.Tasks[j] = taskChangeOutput{Task: tc.Task, changeOutput: newChangeOutput(tc.Change)}
+		}
+		out[i] = o
+	}
+	return out
+}
+
+// csvHeader implements table. Every trend has a row per task and a total
+// row with an empty task.
+func (t trendsOutput) csvHeader() []string {
+	return []string{"name", "task", "current_seconds", "previous_seconds", "delta_seconds", "percent"}
+}
+
+func (t trendsOutput) csvRows() [][]string {
+	var rows [][]string
+	row := func(name, task string, c changeOutput) []string {
+		percent := ""
+		if c.Percent != nil {
+			percent = strconv.FormatFloat(*c.Percent, 'f', -1, 64)
+		}
+		return []string{
+			name,
+			task,
+			strconv.FormatInt(c.Current, 10),
+			strconv.FormatInt(c.Previous, 10),
+			strconv.FormatInt(c.Delta, 10),
+			percent,
+		}
+	}
+	for _, trend := range t {
+		for _, tc := range trend.Tasks {
+			rows = append(rows, row(trend.Name, tc.Task, tc.changeOutput))
+		}
+		rows = append(rows, row(trend.Name, "", trend.Total))
+	}
+	return rows
+}
+
+// budgetsOutput is the schema of budget
+type budgetsOutput []budgetOutput
+
+type budgetOutput struct {
+	Name    string `json:"name" yaml:"name"`
+	Task    string `json:"task" yaml:"task"`
+	Tag     string `json:"tag" yaml:"tag"`
+	Project string `json:"project" yaml:"project"`
+	Period  string `json:"period" yaml:"period"`
+	// PeriodStart and PeriodEnd bound the current period
+	PeriodStart time.Time `json:"period_start" yaml:"period_start"`
+	PeriodEnd   time.Time `json:"period_end" yaml:"period_end"`
+	Limit       int64     `json:"limit_seconds" yaml:"limit_seconds"`
+	Used        int64     `json:"used_seconds" yaml:"used_seconds"`
+	Remaining   int64     `json:"remaining_seconds" yaml:"remaining_seconds"`
+	// Share is the part of the limit used, above 1 when over budget
+	Share     float64 `json:"share" yaml:"share"`
+	Projected int64   `json:"projected_seconds" yaml:"projected_seconds"`
+}
+
+func newBudgetsOutput(statuses []analytics.BudgetStatus) budgetsOutput {
+	out := make(budgetsOutput, len(statuses))
+	for i, s := range statuses {
+		out[i] = budgetOutput{
+			Name:        s.Name,
+			Task:        s.Task,
+			Tag:         s.Tag,
+			Project:     s.Project,
+			Period:      s.Period,
+			PeriodStart: s.Current.From,
+			PeriodEnd:   s.Current.To,
+			Limit:       seconds(s.Limit),
+			Used:        seconds(s.Used),
+			Remaining:   seconds(s.Remaining()),
+			Share:       s.Share(),
+			Projected:   seconds(s.Projected),
+		}
+	}
+	return out
+}
+
+func (b budgetsOutput) csvHeader() []string {
+	return []string{"name", "task", "tag", "project", "period", "period_start", "period_end",
+		"limit_seconds", "used_seconds", "remaining_seconds", "share", "projected_seconds"}
+}
+
+func (b budgetsOutput) csvRows() [][]string {
+	rows := make([][]string, len(b))
+	for i, o := range b {
+		rows[i] = []string{
+			o.Name, o.Task, o.Tag, o.Project, o.Period,
+			formatTime(o.PeriodStart), formatTime(o.PeriodEnd),
+			strconv.FormatInt(o.Limit, 10),
+			strconv.FormatInt(o.Used, 10),
+			strconv.FormatInt(o.Remaining, 10),
+			strconv.FormatFloat(o.Share, 'f', -1, 64),
+			strconv.FormatInt(o.Projected, 10),
+		}
+	}
+	return rows
+}
+
+// timesheetOutput is the schema of timesheet
+type timesheetOutput struct {
+	// Week is the ISO week, e.g. "2026-W41"
+	Week     string               `json:"week" yaml:"week"`
+	From     string               `json:"from" yaml:"from"`
+	To       string               `json:"to" yaml:"to"`
+	By       string               `json:"by" yaml:"by"`
+	Rounding roundingOutput       `json:"rounding" yaml:"rounding"`
+	Rows     []timesheetRowOutput `json:"rows" yaml:"rows"`
+	Totals   []timesheetDayOutput `json:"totals" yaml:"totals"`
+	Total    int64                `json:"total_seconds" yaml:"total_seconds"`
+	Tracked  int64                `json:"tracked_seconds" yaml:"tracked_seconds"`
+}
+
+type roundingOutput struct {
+	Mode      string `json:"mode" yaml:"mode"`
+	Increment int64  `json:"increment_seconds" yaml:"increment_seconds"`
+	Per       string `json:"per" yaml:"per"`
+	Minimum   int64  `json:"minimum_seconds" yaml:"minimum_seconds"`
+}
+
+type timesheetRowOutput struct {
+	Key     string               `json:"key" yaml:"key"`
+	Days    []timesheetDayOutput `json:"days" yaml:"days"`
+	Total   int64                `json:"total_seconds" yaml:"total_seconds"`
+	Tracked int64                `json:"tracked_seconds" yaml:"tracked_seconds"`
+}
+
+type timesheetDayOutput struct {
+	Day   string `json:"day" yaml:"day"`
+	Total int64  `json:"total_seconds" yaml:"total_seconds"`
+}
+
+func newTimesheetOutput(t *analytics.Timesheet, r analytics.Rounding, days analytics.Days) timesheetOutput {
+	year, week := t.Week()
+	out := timesheetOutput{
+		Week: fmt.Sprintf("%d-W%02d", year, week),
+		From: days.Key(t.Days[0]),
+		To:   days.Key(t.Days[6]),
+		By:   string(t.By),
+		Rounding: roundingOutput{
+			Mode:      r.Mode,
+			Increment: seconds(r.Increment),
+			Per:       r.Per,
+			Minimum:   seconds(r.Minimum),
+		},
+		Rows:    make([]timesheetRowOutput, len(t.Rows)),
+		Total:   seconds(t.Total),
+		Tracked: seconds(t.Tracked),
+	}
+	perDay := func(totals [7]time.Duration) []timesheetDayOutput {
+		out := make([]timesheetDayOutput, len(totals))
+		for i, d := range totals {
+			out[i] = timesheetDayOutput{Day: days.Key(t.Days[i]), Total: seconds(d)}
+		}
+		return out
+	}
+	for i, row := range t.Rows {
+		out.Rows[i] = timesheetRowOutput{Key: row.Key, Days: perDay(row.Days), Total: seconds(row.Total), Tracked: seconds(row.Tracked)}
+	}
+	out.Totals = perDay(t.Totals)
+	return out
+}
+
+// csvHeader implements table. Every row has a column per day of the week
+// and the last row, with the key TOTAL, holds the totals.
+func (t timesheetOutput) csvHeader() []string {
+	header := []string{"key"}
+	for d := time.Monday; d <= time.Saturday; d++ {
+		header = append(header, strings.ToLower(d.String())+"_seconds")
+	}
+	return append(header, "sunday_seconds", "total_seconds", "tracked_seconds")
+}
+
+func (t timesheetOutput) csvRows() [][]string {
+	row := func(key string, days []timesheetDayOutput, total, tracked int64) []string {
+		cells := []string{key}
+		for _, d := range days {
+			cells = append(cells, strconv.FormatInt(d.Total, 10))
+		}
+		return append(cells, strconv.FormatInt(total, 10), strconv.FormatInt(tracked, 10))
+	}
+	rows := make([][]string, 0, len(t.Rows)+1)
+	for _, r := range t.Rows {
+		rows = append(rows, row(r.Key, r.Days, r.Total, r.Tracked))
+	}
+	return append(rows, row("TOTAL", t.Totals, t.Total, t.Tracked))
+}
+
+// standupOutput is the schema of standup
+type standupOutput []standupDayOutput
+
+type standupDayOutput struct {
+	Day      string                 `json:"day" yaml:"day"`
+	Total    int64                  `json:"total_seconds" yaml:"total_seconds"`
+	Projects []standupProjectOutput `json:"projects" yaml:"projects"`
+}
+
+type standupProjectOutput struct {
+	Project string              `json:"project" yaml:"project"`
+	Total   int64               `json:"total_seconds" yaml:"total_seconds"`
+	Tasks   []standupTaskOutput `json:"tasks" yaml:"tasks"`
+}
+
+type standupTaskOutput struct {
+	Task     string   `json:"task" yaml:"task"`
+	Duration int64    `json:"duration_seconds" yaml:"duration_seconds"`
+	Tags     []string `json:"tags" yaml:"tags"`
+	Notes    []string `json:"notes" yaml:"notes"`
+	Running  bool     `json:"running" yaml:"running"`
+}
+
+func newStandupOutput(standup []analytics.StandupDay, days analytics.Days) standupOutput {
+	out := make(standupOutput, len(standup))
+	for i, day := range standup {
+		d := standupDayOutput{Day: days.Key(day.Day), Total: seconds(day.Total), Projects: []standupProjectOutput{}}
+		for _, p := range day.Projects {
+			po := standupProjectOutput{Project: p.Project, Total: seconds(p.Total)}
+			for _, t := range p.Tasks {
+				to := standupTaskOutput{Task: t.Task, Duration: seconds(t.Duration), Tags: t.Tags, Notes: t.Notes, Running: t.Running}
+				if to.Tags == nil {
+					to.Tags = []string{}
+				}
+				if to.Notes == nil {
+					to.Notes = []string{}
+				}
+				po.Tasks = append(po.Tasks, to)
+			}
+			d.Projects = append(d.Projects, po)
+		}
+		out[i] = d
+	}
+	return out
+}
+
+// csvHeader implements table with one row per task of each day. Tags and
+// notes are separated by ";".
+func (s standupOutput) csvHeader() []string {
+	return []string{"day", "project", "task", "duration_seconds", "tags", "notes", "running"}
+}
+
+func (s standupOutput) csvRows() [][]string {
+	var rows [][]string
+	for _, d := range s {
+		for _, p := range d.Projects {
+			for _, t := range p.Tasks {
+				rows = append(rows, []string{
+					d.Day, p.Project, t.Task,
+					strconv.FormatInt(t.Duration, 10),
+					strings.Join(t.Tags, ";"),
+					strings.Join(t.Notes, ";"),
+					strconv.FormatBool(t.Running),
+				})
+			}
+		}
+	}
+	return rows
+}
+
+// scoreOutput is the schema of score
+type scoreOutput struct {
+	Score      float64 `json:"score" yaml:"score"`
+	WindowDays int     `json:"window_days" yaml:"window_days"`
+	// From and To bound the window
+	From       time.Time              `json:"from" yaml:"from"`
+	To         time.Time              `json:"to" yaml:"to"`
+	Components []scoreComponentOutput `json:"components" yaml:"components"`
+	History    []scorePointOutput     `json:"history" yaml:"history"`
+}
+
+type scoreComponentOutput struct {
+	Name string `json:"name" yaml:"name"`
+	// Unit of the value and the target, "seconds" or "days"
+	Unit   string  `json:"unit" yaml:"unit"`
+	Value  float64 `json:"value" yaml:"value"`
+	Target float64 `json:"target" yaml:"target"`
+	Ratio  float64 `json:"ratio" yaml:"ratio"`
+	Weight float64 `json:"weight" yaml:"weight"`
+	Points float64 `json:"points" yaml:"points"`
+}
+
+type scorePointOutput struct {
+	Day   string  `json:"day" yaml:"day"`
+	Score float64 `json:"score" yaml:"score"`
+}
+
+func newScoreOutput(score analytics.Score, settings analytics.ScoreSettings, history []analytics.ScorePoint, days analytics.Days) scoreOutput {
+	out := scoreOutput{
+		Score:      score.Total,
+		WindowDays: settings.WindowDays,
+		From:       score.Window.From,
+		To:         score.Window.To,
+		Components: make([]scoreComponentOutput, len(score.Components)),
+		History:    make([]scorePointOutput, len(history)),
+	}
+	for i, c := range score.Components {
+		o := scoreComponentOutput{Name: c.Name, Unit: c.Unit, Value: c.Value, Target: c.Target, Ratio: c.Ratio, Weight: c.Weight, Points: c.Points}
+		if c.Unit == "hours" {
+			o.Unit = "seconds"
+			o.Value = float64(seconds(time.Duration(c.Value * float64(time.Hour))))
+			o.Target = float64(seconds(time.Duration(c.Target * float64(time.Hour))))
+		}
+		out.Components[i] = o
+	}
+	for i, p := range history {
+		out.History[i] = scorePointOutput{Day: days.Key(p.Day), Score: p.Score}
+	}
+	return out
+}
+
+// csvHeader implements table. CSV has one row per component and a total
+// row, the history is only written in JSON and YAML.
+func (s scoreOutput) csvHeader() []string {
+	return []string{"name", "unit", "value", "target", "ratio", "weight", "points"}
+}
+
+func (s scoreOutput) csvRows() [][]string {
+	ftoa := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+	rows := make([][]string, 0, len(s.Components)+1)
+	for _, c := range s.Components {
+		rows = append(rows, []string{c.Name, c.Unit, ftoa(c.Value), ftoa(c.Target), ftoa(c.Ratio), ftoa(c.Weight), ftoa(c.Points)})
+	}
+	return append(rows, []string{"total", "", "", "", "", "1", ftoa(s.Score)})
+}
+
+// pomodoroStatusOutput is the schema of the status of the Pomodoro timer
+type pomodoroStatusOutput struct {
+	Running    bool       `json:"running" yaml:"running"`
+	Task       string     `json:"task" yaml:"task"`
+	State      string     `json:"state" yaml:"state"`
+	Kind       string     `json:"kind" yaml:"kind"`
+	PhaseStart *time.Time `json:"phase_start" yaml:"phase_start"`
+	Remaining  int64      `json:"remaining_seconds" yaml:"remaining_seconds"`
+	Elapsed    int64      `json:"elapsed_seconds" yaml:"elapsed_seconds"`
+	Cycles     int        `json:"cycles" yaml:"cycles"`
+}
+
+func newPomodoroStatusOutput(s *pkgPomodoro.Status) pomodoroStatusOutput {
+	if s == nil {
+		return pomodoroStatusOutput{}
+	}
+	return pomodoroStatusOutput{
+		Running:    true,
+		Task:       s.Task,
+		State:      s.State,
+		Kind:       s.Kind,
+		PhaseStart: &s.PhaseStart,
+		Remaining:  seconds(s.Remaining),
+		Elapsed:    seconds(s.Elapsed),
+		Cycles:     s.Cycles,
+	}
+}
+
+func (s pomodoroStatusOutput) csvHeader() []string {
+	return []string{"running", "task", "state", "kind", "phase_start", "remaining_seconds", "elapsed_seconds", "cycles"}
+}
+
+func (s pomodoroStatusOutput) csvRows() [][]string {
+	start := ""
+	if s.PhaseStart != nil {
+		start = formatTime(*s.PhaseStart)
+	}
+	return [][]string{{
+		strconv.FormatBool(s.Running),
+		s.Task,
+		s.State,
+		s.Kind,
+		start,
+		strconv.FormatInt(s.Remaining, 10),
+		strconv.FormatInt(s.Elapsed, 10),
+		strconv.Itoa(s.Cycles),
+	}}
+}
+
+// interruptionOutput is the schema of a recorded interruption
+type interruptionOutput struct {
+	Task          string    `json:"task" yaml:"task"`
+	Kind          string    `json:"kind" yaml:"kind"`
+	Reason        string    `json:"reason" yaml:"reason"`
+	Time          time.Time `json:"time" yaml:"time"`
+	IntervalStart time.Time `json:"interval_start" yaml:"interval_start"`
+	Void          bool      `json:"void" yaml:"void"`
+}
+
+func newInterruptionOutput(i *models.Interruption) interruptionOutput {
+	return interruptionOutput{
+		Task:          i.Task,
+		Kind:          i.Kind(),
+		Reason:        i.Reason,
+		Time:          i.Time,
+		IntervalStart: i.IntervalStart,
+		Void:          i.Void,
+	}
+}
+
+func (i interruptionOutput) csvHeader() []string {
+	return []string{"task", "kind", "reason", "time", "interval_start", "void"}
+}
+
+func (i interruptionOutput) csvRows() [][]string {
+	return [][]string{{i.Task, i.Kind, i.Reason, formatTime(i.Time), formatTime(i.IntervalStart), strconv.FormatBool(i.Void)}}
+}
+
+// settingsOutput is the schema of config settings
+type settingsOutput []settingOutput
+
+type settingOutput struct {
+	Key   string `json:"key" yaml:"key"`
+	Value string `json:"value" yaml:"value"`
+	// Source is "env" for settings overridden by a GOTRACK_* variable,
+	// "default" after unset, and "config" otherwise
+	Source string `json:"source" yaml:"source"`
+}
+
+// newSettingOutput returns a setting of the loaded config
+func newSettingOutput(key, value string) settingOutput {
+	source := "config"
+	if slices.Contains(envOverrides, key) {
+		source = "env"
+	}
+	return settingOutput{Key: key, Value: value, Source: source}
+}
+
+func (s settingsOutput) csvHeader() []string { return []string{"key", "value", "source"} }
+
+func (s settingsOutput) csvRows() [][]string {
+	rows := make([][]string, len(s))
+	for i, setting := range s {
+		rows[i] = []string{setting.Key, setting.Value, setting.Source}
+	}
+	return rows
+}
+
+// configPathOutput is the schema of config path and config reset
+type configPathOutput struct {
+	Path string `json:"path" yaml:"path"`
+}
+
+func (p configPathOutput) csvHeader() []string { return []string{"path"} }
+
+func (p configPathOutput) csvRows() [][]string { return [][]string{{p.Path}} }
+
+// validationOutput is the schema of config validate
+type validationOutput struct {
+	Valid  bool               `json:"valid" yaml:"valid"`
+	Errors []fieldErrorOutput `json:"errors" yaml:"errors"`
+}
+
+type fieldErrorOutput struct {
+	Field   string `json:"field" yaml:"field"`
+	Line    int    `json:"line" yaml:"line"`
+	Message string `json:"message" yaml:"message"`
+}
+
+func newValidationOutput(verr *config.ValidationError) validationOutput {
+	out := validationOutput{Valid: verr == nil, Errors: []fieldErrorOutput{}}
+	if verr != nil {
+		for _, fe := range verr.Errors {
+			out.Errors = append(out.Errors, fieldErrorOutput{Field: fe.Field, Line: fe.Line, Message: fe.Message})
+		}
+	}
+	return out
+}
+
+func (v validationOutput) csvHeader() []string { return []string{"field", "line", "message"} }
+
+func (v validationOutput) csvRows() [][]string {
+	rows := make([][]string, len(v.Errors))
+	for i, fe := range v.Errors {
+		rows[i] = []string{fe.Field, strconv.Itoa(fe.Line), fe.Message}
+	}
+	return rows
+}
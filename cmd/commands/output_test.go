@@ -0,0 +1,258 @@
+package cmd_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmd "github.com/AndriyBarskyi/gotrack/cmd/commands"
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
+	pkgPomodoro "github.com/AndriyBarskyi/gotrack/internal/tracker/pomodoro"
+)
+
+var update = flag.Bool("update", false, "Update the golden files in testdata")
+
+var (
+	testNow   = time.Date(2024, 6, 5, 12, 0, 0, 0, time.UTC)
+	testDays  = analytics.Days{Location: time.UTC}
+	testScore = analytics.ScoreSettings{
+		WindowDays:        7,
+		VolumeTarget:      10 * time.Hour,
+		ActiveDaysTarget:  5,
+		StreakTarget:      3,
+		VolumeWeight:      0.4,
+		ConsistencyWeight: 0.4,
+		StreakWeight:      0.2,
+	}
+)
+
+func testSessions() []models.Session {
+	day := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	return []models.Session{
+		{Task: "coding", Project: "gotrack", Tags: []string{"go", "oss"}, StartTime: day.Add(9 * time.Hour), EndTime: day.Add(11 * time.Hour)},
+		{Task: "review", Project: "gotrack", StartTime: day.Add(14 * time.Hour), EndTime: day.Add(14*time.Hour + 30*time.Minute), Note: "budget PR"},
+		{Task: "email, chat", StartTime: day.Add(33 * time.Hour), EndTime: day.Add(34*time.Hour + 15*time.Minute)},
+	}
+}
+
+// assertGolden compares the output of every machine-readable format with the
+// golden files testdata/<name>.<format>, or rewrites them with -update
+func assertGolden(t *testing.T, name string, write func(buf *bytes.Buffer, format string) error) {
+	for _, format := range cmd.OutputFormats {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, write(&buf, format))
+
+			assertGoldenFile(t, name+"."+format, buf.String())
+		})
+	}
+}
+
+// assertGoldenFile compares got with the golden file testdata/<file>, or
+// rewrites it with -update
+func assertGoldenFile(t *testing.T, file, got string) {
+	path := filepath.Join("testdata", file)
+	if *update {
+		require.NoError(t, os.MkdirAll("testdata", 0755))
+		require.NoError(t, os.WriteFile(path, []byte(got), 0644))
+	}
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err, "run go test with -update to create the golden files")
+	assert.Equal(t, string(expected), got)
+}
+
+func TestOutput_Sessions(t *testing.T) {
+	running := models.Session{Task: "coding", StartTime: testNow.Add(-45 * time.Minute)}
+	out := cmd.NewSessionListOutput(append(testSessions(), running), testNow)
+
+	assertGolden(t, "sessions", func(buf *bytes.Buffer, format string) error {
+		return cmd.WriteOutputAs(buf, format, out)
+	})
+}
+
+func TestOutput_Session(t *testing.T) {
+	ssn := testSessions()[0]
+
+	assertGolden(t, "session", func(buf *bytes.Buffer, format string) error {
+		return cmd.WriteOutputAs(buf, format, cmd.NewSingleSessionOutput(&ssn, testNow))
+	})
+	assertGolden(t, "no_session", func(buf *bytes.Buffer, format string) error {
+		return cmd.WriteOutputAs(buf, format, cmd.NewSingleSessionOutput(nil, testNow))
+	})
+}
+
+func TestOutput_Stats(t *testing.T) {
+	day := time.Date(2024, 6, 4, 0, 0, 0, 0, time.UTC)
+	poms := []models.Pomodoro{
+		{Task: "coding", StartTime: day.Add(9 * time.Hour), EndTime: day.Add(9*time.Hour + 25*time.Minute), Planned: 25 * time.Minute, Completed: true},
+		{Task: "coding", StartTime: day.Add(10 * time.Hour), EndTime: day.Add(10*time.Hour + 10*time.Minute), Planned: 25 * time.Minute},
+	}
+	ints := []models.Interruption{
+		{Task: "coding", IntervalStart: day.Add(10 * time.Hour), Time: day.Add(10*time.Hour + 10*time.Minute), External: true},
+	}
+	out := cmd.NewStatsOutput(testSessions(), poms, ints, "", 4, testScore, testDays, testNow)
+
+	assertGolden(t, "stats", func(buf *bytes.Buffer, format string) error {
+		return cmd.WriteOutputAs(buf, format, out)
+	})
+}
+
+func TestOutput_Report(t *testing.T) {
+	query := analytics.Query{
+		GroupBy: []analytics.GroupBy{analytics.GroupProject, analytics.GroupDay},
+		Days:    testDays,
+		Now:     testNow,
+	}
+	report := analytics.Run(testSessions(), query)
+
+	assertGolden(t, "report", func(buf *bytes.Buffer, format string) error {
+		return cmd.WriteOutputAs(buf, format, cmd.NewReportOutput(report, []float64{50, 90}))
+	})
+
+	query.GroupBy = nil
+	report = analytics.Run(testSessions(), query)
+	assertGolden(t, "report_total", func(buf *bytes.Buffer, format string) error {
+		return cmd.WriteOutputAs(buf, format, cmd.NewReportOutput(report, []float64{50}))
+	})
+}
+
+func TestOutput_Profile(t *testing.T) {
+	profile := analytics.BuildProfile(testSessions(), analytics.Query{Days: testDays, Now: testNow})
+
+	assertGolden(t, "profile", func(buf *bytes.Buffer, format string) error {
+		return cmd.WriteOutputAs(buf, format, cmd.NewProfileOutput(profile))
+	})
+}
+
+func TestOutput_Trends(t *testing.T) {
+	trends := analytics.Trends(testSessions(), analytics.Query{Days: testDays, Now: testNow.AddDate(0, 0, 7)})
+
+	assertGolden(t, "trends", func(buf *bytes.Buffer, format string) error {
+		return cmd.WriteOutputAs(buf, format, cmd.NewTrendsOutput(trends))
+	})
+}
+
+func TestOutput_Budgets(t *testing.T) {
+	budgets := []analytics.Budget{
+		{Name: "gotrack", Project: "gotrack", Limit: 3 * time.Hour, Period: "week"},
+		{Name: "open-source", Tag: "oss", Limit: 10 * time.Hour, Period: "month"},
+	}
+	var statuses []analytics.BudgetStatus
+	for _, b := range budgets {
+		statuses = append(statuses, analytics.CheckBudget(testSessions(), b, testDays, testNow))
+	}
+
+	assertGolden(t, "budgets", func(buf *bytes.Buffer, format string) error {
+		return cmd.WriteOutputAs(buf, format, cmd.NewBudgetsOutput(statuses))
+	})
+}
+
+func TestOutput_Focus(t *testing.T) {
+	focus := analytics.BuildFocus(testSessions(), analytics.Query{Days: testDays, Now: testNow})
+
+	assertGolden(t, "focus", func(buf *bytes.Buffer, format string) error {
+		return cmd.WriteOutputAs(buf, format, cmd.NewFocusOutput(focus, testDays))
+	})
+}
+
+func TestOutput_Timesheet(t *testing.T) {
+	rounding := analytics.Rounding{Mode: analytics.RoundUp, Increment: 15 * time.Minute, Per: analytics.RoundPerDay, Minimum: 15 * time.Minute}
+	monday := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	sheet := analytics.BuildTimesheet(testSessions(), analytics.Query{Days: testDays, Now: testNow}, monday, analytics.GroupProject, rounding)
+
+	assertGolden(t, "timesheet", func(buf *bytes.Buffer, format string) error {
+		return cmd.WriteOutputAs(buf, format, cmd.NewTimesheetOutput(sheet, rounding, testDays))
+	})
+}
+
+func TestTimesheetMarkdown(t *testing.T) {
+	rounding := analytics.Rounding{Mode: analytics.RoundNearest, Increment: 15 * time.Minute, Per: analytics.RoundPerSession}
+	monday := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	ssns := append(testSessions(), models.Session{
+		Task:      "fix | escape",
+		StartTime: monday.Add(16 * time.Hour),
+		EndTime:   monday.Add(16*time.Hour + 20*time.Minute),
+	})
+	sheet := analytics.BuildTimesheet(ssns, analytics.Query{Days: testDays, Now: testNow}, monday, analytics.GroupTask, rounding)
+
+	var buf bytes.Buffer
+	require.NoError(t, cmd.WriteTimesheetMarkdown(&buf, sheet, rounding))
+	assertGoldenFile(t, "timesheet.md", buf.String())
+
+	buf.Reset()
+	empty := analytics.BuildTimesheet(nil, analytics.Query{Days: testDays, Now: testNow}, monday, analytics.GroupTask, rounding)
+	require.NoError(t, cmd.WriteTimesheetMarkdown(&buf, empty, rounding))
+	assertGoldenFile(t, "timesheet_empty.md", buf.String())
+}
+
+func TestOutput_Standup(t *testing.T) {
+	standup := []analytics.StandupDay{
+		analytics.BuildStandupDay(testSessions(), testDays, time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC), testNow),
+		analytics.BuildStandupDay(testSessions(), testDays, time.Date(2024, 6, 5, 0, 0, 0, 0, time.UTC), testNow),
+	}
+
+	assertGolden(t, "standup", func(buf *bytes.Buffer, format string) error {
+		return cmd.WriteOutputAs(buf, format, cmd.NewStandupOutput(standup, testDays))
+	})
+}
+
+func TestStandupMarkdown(t *testing.T) {
+	standup := []analytics.StandupDay{
+		analytics.BuildStandupDay(testSessions(), testDays, time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC), testNow),
+		analytics.BuildStandupDay(testSessions(), testDays, time.Date(2024, 6, 4, 0, 0, 0, 0, time.UTC), testNow),
+		analytics.BuildStandupDay(testSessions(), testDays, time.Date(2024, 6, 5, 0, 0, 0, 0, time.UTC), testNow),
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, cmd.WriteStandup(&buf, standup, testDays, testNow, true))
+	assertGoldenFile(t, "standup.md", buf.String())
+
+	buf.Reset()
+	require.NoError(t, cmd.WriteStandup(&buf, standup, testDays, testNow, false))
+	assertGoldenFile(t, "standup.txt", buf.String())
+}
+
+func TestOutput_Score(t *testing.T) {
+	score := analytics.CalculateScore(testSessions(), testScore, testDays, testNow)
+	history := analytics.ScoreHistory(testSessions(), testScore, testDays, testNow, 2, 7)
+
+	assertGolden(t, "score", func(buf *bytes.Buffer, format string) error {
+		return cmd.WriteOutputAs(buf, format, cmd.NewScoreOutput(score, testScore, history, testDays))
+	})
+}
+
+func TestOutput_Heatmap(t *testing.T) {
+	from := time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC)
+	heatmap := analytics.BuildHeatmap(testSessions(), "", testDays, from, testNow, testNow)
+
+	assertGolden(t, "heatmap", func(buf *bytes.Buffer, format string) error {
+		return cmd.WriteOutputAs(buf, format, cmd.NewHeatmapOutput(heatmap, testDays))
+	})
+}
+
+func TestOutput_PomodoroStatus(t *testing.T) {
+	status := &pkgPomodoro.Status{
+		Task:       "coding",
+		State:      "Work",
+		Kind:       "work",
+		PhaseStart: testNow.Add(-10 * time.Minute),
+		Remaining:  15 * time.Minute,
+		Elapsed:    10 * time.Minute,
+		Cycles:     2,
+		UpdatedAt:  testNow,
+	}
+
+	assertGolden(t, "pomodoro_status", func(buf *bytes.Buffer, format string) error {
+		return cmd.WriteOutputAs(buf, format, cmd.NewPomodoroStatusOutput(status))
+	})
+	assertGolden(t, "pomodoro_status_idle", func(buf *bytes.Buffer, format string) error {
+		return cmd.WriteOutputAs(buf, format, cmd.NewPomodoroStatusOutput(nil))
+	})
+}
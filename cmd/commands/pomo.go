@@ -1,30 +1,43 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	cfg "github.com/AndriyBarskyi/gotrack/internal/config"
+	"github.com/AndriyBarskyi/gotrack/internal/hooks"
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/notify"
+	"github.com/AndriyBarskyi/gotrack/internal/terminal"
 	"github.com/AndriyBarskyi/gotrack/internal/tracker"
 	pkgPomodoro "github.com/AndriyBarskyi/gotrack/internal/tracker/pomodoro"
 )
 
 type pomoCmd struct {
 	sessionManager *tracker.SessionManager
-	workDuration   time.Duration
-	breakDuration  time.Duration
+	workDuration   cfg.Duration
+	breakDuration  cfg.Duration
 	cycles         int
+	mode           string
 }
 
 // NewPomoCmd creates a new pomodoro command
 func NewPomoCmd(sm *tracker.SessionManager) *cobra.Command {
 	cmd := &pomoCmd{
 		sessionManager: sm,
+		workDuration:   cfg.Default().Pomodoro.WorkDuration,
+		breakDuration:  cfg.Default().Pomodoro.BreakDuration,
 	}
 
 	cobraCmd := &cobra.Command{
@@ -33,7 +46,27 @@ func NewPomoCmd(sm *tracker.SessionManager) *cobra.Command {
 		Long: `Start a Pomodoro timer with work and break intervals.
 
 By default, it runs for 25 minutes of work followed by 5 minutes of break.
-You can customize the durations using the flags.`,
+You can customize the durations using the flags.
+
+Other timer modes can be selected with --mode:
+  classic    work, short breaks and a long break every few sessions (default)
+  flowtime   open-ended work, press Enter to take a break proportional to it
+  52/17      any fixed work/break ratio in minutes
+  <name>     a custom sequence defined under pomodoro.sequences in the config
+
+While the timer runs, these keys control it:
+  space      pause or resume
+  s          skip to the next phase
+  + / -      extend or shorten the current phase by a minute
+  i          record an internal interruption of the work interval
+  e          record an external interruption of the work interval
+  q          stop the timer and finish the session
+
+Interruptions can also be recorded from another terminal with
+'gotrack pomo interrupt', which can void the work interval as well.
+
+"status" and "interrupt" are subcommands rather than task names. To time a
+task with one of these names, put it after --, e.g. 'gotrack pomo -- status'.`,
 		Example: `
   # Start a default Pomodoro (25m work, 5m break)
   gotrack pomo "Coding"
@@ -43,14 +76,24 @@ You can customize the durations using the flags.`,
 
   # Run multiple cycles
   gotrack pomo "Studying" --cycles 4
+
+  # Work until you lose focus, then take a proportional break
+  gotrack pomo "Research" --mode flowtime
 `,
 		Args: cobra.ExactArgs(1),
 		RunE: cmd.run,
+		Annotations: map[string]string{
+			textOutputOnly: "true",
+		},
 	}
 
-	cobraCmd.Flags().DurationVarP(&cmd.workDuration, "work", "w", cfg.Default().Pomodoro.WorkDuration, "Work duration")
-	cobraCmd.Flags().DurationVarP(&cmd.breakDuration, "break", "b", cfg.Default().Pomodoro.BreakDuration, "Break duration")
+	cobraCmd.Flags().VarP(&cmd.workDuration, "work", "w", "Work duration, e.g. 50m or 50")
+	cobraCmd.Flags().VarP(&cmd.breakDuration, "break", "b", "Break duration, e.g. 10m or 10")
 	cobraCmd.Flags().IntVarP(&cmd.cycles, "cycles", "c", 1, "Number of work/break cycles")
+	cobraCmd.Flags().StringVarP(&cmd.mode, "mode", "m", "", "Timer mode: classic, flowtime, a ratio like 52/17 or a custom sequence")
+
+	cobraCmd.AddCommand(NewInterruptCmd())
+	cobraCmd.AddCommand(NewPomoStatusCmd())
 
 	return cobraCmd
 }
@@ -68,32 +111,108 @@ func (c *pomoCmd) run(cmd *cobra.Command, args []string) error {
 	}
 
 	pomodoroCfg := appConfig.Pomodoro
-	if c.workDuration != cfg.Default().Pomodoro.WorkDuration {
+	if cmd.Flags().Changed("work") {
 		pomodoroCfg.WorkDuration = c.workDuration
 	}
-	if c.breakDuration != cfg.Default().Pomodoro.BreakDuration {
+	if cmd.Flags().Changed("break") {
 		pomodoroCfg.BreakDuration = c.breakDuration
 	}
 
-	pomodoro := pkgPomodoro.New(&pomodoroCfg)
+	if c.mode != "" {
+		pomodoroCfg.Mode = c.mode
+	}
+
+	sequence, err := pkgPomodoro.NewSequence(&pomodoroCfg)
+	if err != nil {
+		return err
+	}
+	pomodoro := pkgPomodoro.NewWithSequence(&pomodoroCfg, sequence)
+
+	notifier, err := notify.New(pomodoroCfg.Notifications, os.Stdout)
+	if err != nil {
+		return fmt.Errorf("failed to set up notifications: %v", err)
+	}
+	pomodoro.OnAdvance(pkgPomodoro.PhaseNotifier(notifier, func(err error) {
+		fmt.Fprintf(os.Stderr, "\nNotification failed: %v\n", err)
+	}))
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
-	if _, err := sm.Start(taskName); err != nil {
+	template, err := newSession(taskName)
+	if err != nil {
+		return err
+	}
+	session, err := sm.StartSession(template)
+	if err != nil {
 		return fmt.Errorf("failed to start work session: %v", err)
 	}
+	fireHook(hooks.SessionStarted, session.Task, nil)
 
+	finish := func() {
+		finished, err := sm.Finish()
+		if err != nil {
+			fmt.Printf("Error finishing session: %v\n", err)
+			return
+		}
+		fireHook(hooks.SessionFinished, finished.Task, map[string]string{
+			"duration": finished.Duration().Round(time.Second).String(),
+		})
+	}
+
+	pomodoro.OnPhaseEnd(func(e pkgPomodoro.PhaseEnd) {
+		if e.Phase.Kind == pkgPomodoro.KindWork {
+			recordPomodoro(taskName, e)
+		}
+	})
+
+	// State changes come from the key loop and from the timer's goroutine,
+	// output is guarded by mu so that they do not interleave
+	var mu sync.Mutex
+	previous := pkgPomodoro.StateIdle
 	pomodoro.OnStateChange(func(s pkgPomodoro.State) {
-		switch s {
-		case pkgPomodoro.StateWorking:
-			fmt.Printf("\n\nStarting work session\n")
-		case pkgPomodoro.StateShortBreak, pkgPomodoro.StateLongBreak:
+		mu.Lock()
+		defer mu.Unlock()
+		resumed := previous == pkgPomodoro.StatePaused && pomodoro.Elapsed() > 0
+		previous = s
+		switch {
+		case s == pkgPomodoro.StatePaused:
+			fmt.Printf("\n\nPaused, press space to resume\n")
+		case resumed:
+			fmt.Printf("\n\nResuming %s\n", s.String())
+		case s.IsWork() && pomodoro.Phase().OpenEnded():
+			fmt.Printf("\n\nStarting %s, press Enter to take a break\n", workLabel(s))
+		case s.IsWork():
+			fmt.Printf("\n\nStarting %s\n", workLabel(s))
+		case s.IsBreak():
 			fmt.Printf("\n\nStarting %s\n", s.String())
 		}
+		publishStatus(pomodoro, taskName)
+
+		if event := pomodoroEvent(s); event != "" {
+			fireHook(event, taskName, map[string]string{
+				"state":     s.String(),
+				"kind":      s.Kind.String(),
+				"cycles":    strconv.Itoa(pomodoro.Cycles()),
+				"remaining": pomodoro.Remaining().Round(time.Second).String(),
+			})
+		}
 	})
 
-	fmt.Printf("Press Ctrl+C to stop the session...")
+	restore, err := terminal.MakeCbreak(int(os.Stdin.Fd()))
+	switch {
+	case err == nil:
+		defer func() {
+			if err := restore(); err != nil {
+				fmt.Fprintf(os.Stderr, "\n%v\n", err)
+			}
+		}()
+	case !errors.Is(err, terminal.ErrNotTerminal):
+		fmt.Fprintf(os.Stderr, "Keyboard controls unavailable: %v\n", err)
+	}
+	keys := readKeys(os.Stdin, err == nil)
+
+	fmt.Printf("Keys: space pause/resume, s skip, +/- adjust by %s, i/e interruption, q quit", phaseAdjustStep)
 
 	if err := pomodoro.Start(); err != nil {
 		return fmt.Errorf("failed to start Pomodoro: %v", err)
@@ -101,48 +220,281 @@ func (c *pomoCmd) run(cmd *cobra.Command, args []string) error {
 
 	ticker := time.NewTicker(500 * time.Millisecond)
 	defer ticker.Stop()
+	defer os.Remove(pomodoroStatusPath)
 
+	stop := func() {
+		fmt.Println("\nStopping Pomodoro session...")
+		pomodoro.Stop()
+		finish()
+	}
+
+	ticks := 0
+	voids := &voidWatch{}
 	for {
 		select {
-		case <-sigChan:
-			fmt.Println("\nStopping Pomodoro session...")
-			pomodoro.Stop()
-			if _, err := sm.Finish(); err != nil {
-				fmt.Printf("Error finishing session: %v\n", err)
+		case key := <-keys:
+			if !handleKey(pomodoro, taskName, key) {
+				stop()
+				return nil
 			}
+			publishStatus(pomodoro, taskName)
+		case <-sigChan:
+			stop()
 			return nil
 		case <-ticker.C:
 			state := pomodoro.State()
 			if state == pkgPomodoro.StateIdle {
 				fmt.Println("\nPomodoro session completed!")
-				if _, err := sm.Finish(); err != nil {
-					fmt.Printf("Error finishing session: %v\n", err)
-				}
+				finish()
 				return nil
 			}
 
+			ticks++
+			if ticks%statusRefreshTicks == 0 {
+				publishStatus(pomodoro, taskName)
+			}
+			if voided := restartIfVoided(pomodoro, voids); voided != nil {
+				mu.Lock()
+				fmt.Printf("\n\nWork interval voided by %s interruption, starting over", voided.Kind())
+				mu.Unlock()
+			}
+
+			// Open-ended phases count up instead of down
 			remaining := max(pomodoro.Remaining(), 0)
+			sign := ""
+			if pomodoro.Phase().OpenEnded() {
+				remaining = pomodoro.Elapsed()
+				sign = "+"
+			}
 
 			hours := int(remaining.Hours())
 			minutes := int(remaining.Minutes()) % 60
 			seconds := int(remaining.Seconds()) % 60
 
 			stateStr := ""
-			switch state {
-			case pkgPomodoro.StateWorking:
+			switch {
+			case state.IsWork():
 				stateStr = "Work"
-			case pkgPomodoro.StateShortBreak:
+				if state != pkgPomodoro.StateWorking {
+					stateStr += " (" + state.String() + ")"
+				}
+			case state == pkgPomodoro.StateShortBreak:
 				stateStr = "Short Break"
-			case pkgPomodoro.StateLongBreak:
+			case state == pkgPomodoro.StateLongBreak:
 				stateStr = "Long Break"
-			case pkgPomodoro.StatePaused:
+			case state == pkgPomodoro.StatePaused:
 				stateStr = "Paused"
 			default:
-				stateStr = state.String()
+				stateStr = strings.ToUpper(state.String()[:1]) + state.String()[1:]
 			}
 
-			fmt.Printf("\r%s: %s | %02d:%02d:%02d", stateStr, taskName, hours, minutes, seconds)
+			mu.Lock()
+			fmt.Printf("\r%s: %s | %s%02d:%02d:%02d", stateStr, taskName, sign, hours, minutes, seconds)
 			os.Stdout.Sync()
+			mu.Unlock()
+		}
+	}
+}
+
+// phaseAdjustStep is how much the + and - keys lengthen or shorten a phase
+const phaseAdjustStep = time.Minute
+
+// readKeys delivers key presses read from r. In cbreak mode every byte is a
+// key; otherwise input arrives a line at a time and each line counts as the
+// key it starts with, or Enter when it is empty.
+func readKeys(r io.Reader, cbreak bool) <-chan byte {
+	keys := make(chan byte)
+	go func() {
+		if cbreak {
+			buf := make([]byte, 1)
+			for {
+				n, err := r.Read(buf)
+				if err != nil {
+					return
+				}
+				if n == 1 {
+					keys <- buf[0]
+				}
+			}
+		}
+
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			if line := scanner.Text(); line != "" {
+				keys <- line[0]
+			} else {
+				keys <- '\n'
+			}
 		}
+	}()
+	return keys
+}
+
+// handleKey applies a key press to the timer running for task. It returns
+// false when the key asks to quit.
+func handleKey(p *pkgPomodoro.Pomodoro, task string, key byte) bool {
+	switch key {
+	case ' ':
+		if p.State() == pkgPomodoro.StatePaused {
+			p.Start()
+		} else {
+			p.Pause()
+		}
+	case 's', 'S':
+		p.Skip()
+	case '+', '=':
+		p.Extend(phaseAdjustStep)
+	case '-', '_':
+		p.Extend(-phaseAdjustStep)
+	case '\n', '\r':
+		// Enter ends open-ended work, as in flowtime mode
+		if p.Phase().OpenEnded() && p.State().IsWork() {
+			p.Skip()
+		}
+	case 'i', 'I':
+		interruptTimer(p, task, false)
+	case 'e', 'E':
+		interruptTimer(p, task, true)
+	case 'q', 'Q':
+		return false
+	}
+	return true
+}
+
+// interruptTimer records an interruption of the running work interval from
+// the keyboard, like 'gotrack pomo interrupt' does from another terminal
+func interruptTimer(p *pkgPomodoro.Pomodoro, task string, external bool) {
+	if interruptionStorage == nil {
+		return
+	}
+	if !p.State().IsWork() {
+		fmt.Printf("\n\nInterruptions can only be recorded during a work interval\n")
+		return
+	}
+
+	fmt.Print("\n\n")
+	err := recordInterruption(&models.Interruption{
+		Task:          task,
+		IntervalStart: p.PhaseStart(),
+		Time:          time.Now(),
+		External:      external,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+	}
+}
+
+// statusRefreshTicks is how many ticks pass between rewrites of the status
+// file, keeping it well within pkgPomodoro.StatusTTL
+const statusRefreshTicks = 10
+
+// publishStatus shares the timer state with other gotrack processes
+func publishStatus(p *pkgPomodoro.Pomodoro, task string) {
+	if pomodoroStatusPath == "" {
+		return
+	}
+	if err := pkgPomodoro.WriteStatus(pomodoroStatusPath, p.Status(task)); err != nil {
+		fmt.Fprintf(os.Stderr, "\nFailed to publish Pomodoro status: %v\n", err)
+	}
+}
+
+// recordPomodoro saves a finished work interval so that it shows up in the
+// pomodoro statistics
+func recordPomodoro(task string, e pkgPomodoro.PhaseEnd) {
+	if pomodoroStorage == nil || e.Elapsed <= 0 {
+		return
+	}
+
+	err := pomodoroStorage.Save(&models.Pomodoro{
+		Task:      task,
+		StartTime: e.Start,
+		EndTime:   e.End,
+		Planned:   e.Phase.Duration,
+		Completed: e.Completed,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\nFailed to record Pomodoro: %v\n", err)
+	}
+}
+
+// workLabel describes a work phase, with its name unless it is the classic
+// one, e.g. "work session (draft)"
+func workLabel(s pkgPomodoro.State) string {
+	if s == pkgPomodoro.StateWorking {
+		return "work session"
+	}
+	return "work session (" + s.String() + ")"
+}
+
+// restartIfVoided restarts the work phase of any timer mode when an
+// interruption voided it, and returns that interruption
+func restartIfVoided(p *pkgPomodoro.Pomodoro, w *voidWatch) *models.Interruption {
+	if !p.State().IsWork() {
+		return nil
+	}
+	voided := w.check(p.PhaseStart())
+	if voided != nil {
+		p.Restart()
+	}
+	return voided
+}
+
+// voidWatch looks for interruptions voiding the work interval, reading the
+// interruptions again only when the file changed since the last check
+type voidWatch struct {
+	modTime time.Time
+	size    int64
+}
+
+// check returns an interruption voiding the work interval that started at
+// intervalStart, or nil if there is none or the file did not change
+func (w *voidWatch) check(intervalStart time.Time) *models.Interruption {
+	if interruptionStorage == nil {
+		return nil
+	}
+	info, err := interruptionStorage.Stat()
+	if err != nil || (info.ModTime().Equal(w.modTime) && info.Size() == w.size) {
+		return nil
+	}
+	w.modTime, w.size = info.ModTime(), info.Size()
+	return voidingInterruption(intervalStart)
+}
+
+// voidingInterruption returns an interruption recorded since the work interval
+// started that asked for the interval to be voided, or nil if there is none
+func voidingInterruption(intervalStart time.Time) *models.Interruption {
+	if interruptionStorage == nil || intervalStart.IsZero() {
+		return nil
+	}
+
+	interruptions, err := interruptionStorage.GetByDateRange(intervalStart, time.Now())
+	if err != nil {
+		return nil
+	}
+	for i := range interruptions {
+		if interruptions[i].Void {
+			return &interruptions[i]
+		}
+	}
+	return nil
+}
+
+// pomodoroEvent returns the hook event fired when the timer enters the state
+func pomodoroEvent(s pkgPomodoro.State) string {
+	switch {
+	case s == pkgPomodoro.StateShortBreak:
+		return hooks.PomodoroShortBreakStarted
+	case s == pkgPomodoro.StateLongBreak:
+		return hooks.PomodoroLongBreakStarted
+	case s.IsWork():
+		return hooks.PomodoroWorkStarted
+	case s.IsBreak():
+		return hooks.PomodoroBreakStarted
+	case s == pkgPomodoro.StatePaused:
+		return hooks.PomodoroPaused
+	case s == pkgPomodoro.StateIdle:
+		return hooks.PomodoroStopped
+	default:
+		return ""
 	}
 }
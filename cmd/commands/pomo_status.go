@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	pkgPomodoro "github.com/AndriyBarskyi/gotrack/internal/tracker/pomodoro"
+)
+
+// NewPomoStatusCmd creates a new pomodoro status command
+func NewPomoStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show the state of the running Pomodoro",
+		Long: `Show the phase, the elapsed and the remaining time of the Pomodoro that is
+running in another terminal.`,
+		Example: `
+  gotrack pomo status
+  gotrack pomo status --output json
+`,
+		Args: cobra.NoArgs,
+		RunE: runPomoStatus,
+	}
+}
+
+func runPomoStatus(cmd *cobra.Command, args []string) error {
+	status, err := pkgPomodoro.ReadStatus(pomodoroStatusPath)
+	if errors.Is(err, pkgPomodoro.ErrNoStatus) {
+		status, err = nil, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if machineOutput() {
+		return writeOutput(newPomodoroStatusOutput(status))
+	}
+
+	if status == nil {
+		fmt.Println("No Pomodoro is running; to time a task named status, run 'gotrack pomo -- status'")
+		return nil
+	}
+	fmt.Printf("%s: %s\n", status.State, color.CyanString(status.Task))
+	fmt.Printf("Elapsed: %s\n", formatDuration(status.Elapsed))
+	if status.Remaining > 0 {
+		fmt.Printf("Remaining: %s\n", formatDuration(status.Remaining))
+	}
+	fmt.Printf("Completed cycles: %d\n", status.Cycles)
+	return nil
+}
@@ -0,0 +1,60 @@
+package cmd_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmd "github.com/AndriyBarskyi/gotrack/cmd/commands"
+	"github.com/AndriyBarskyi/gotrack/internal/config"
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/storage"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/pomodoro"
+)
+
+func TestRestartIfVoided_Flowtime(t *testing.T) {
+	is, err := storage.NewInterruptionStorage(filepath.Join(t.TempDir(), "interruptions.jsonl"))
+	require.NoError(t, err)
+
+	cfg := config.Default().Pomodoro
+	cfg.Mode = pomodoro.ModeFlowtime
+	seq, err := pomodoro.NewSequence(&cfg)
+	require.NoError(t, err)
+	p := pomodoro.NewWithSequence(&cfg, seq)
+	require.NoError(t, p.Start())
+	defer p.Stop()
+
+	start := p.PhaseStart()
+	assert.Nil(t, cmd.RestartIfVoided(p, is), "Nothing voids the flow yet")
+	assert.Equal(t, start, p.PhaseStart())
+
+	require.NoError(t, is.Save(&models.Interruption{
+		Task:          "research",
+		IntervalStart: start,
+		Time:          time.Now(),
+		External:      true,
+		Void:          true,
+	}))
+	voided := cmd.RestartIfVoided(p, is)
+	require.NotNil(t, voided, "A void interruption should restart flowtime work")
+	assert.True(t, voided.Void)
+	assert.True(t, p.State().IsWork())
+	assert.True(t, p.PhaseStart().After(start), "The work phase should start over")
+}
+
+func TestPomoCmd_ReservedTaskNames(t *testing.T) {
+	pomo := cmd.NewPomoCmd(nil)
+
+	found, _, err := pomo.Find([]string{"status"})
+	require.NoError(t, err)
+	assert.Equal(t, "status", found.Name())
+
+	found, args, err := pomo.Find([]string{"--", "status"})
+	require.NoError(t, err)
+	assert.Equal(t, pomo, found, "A task named like a subcommand can follow --")
+	require.NoError(t, found.ParseFlags(args))
+	assert.Equal(t, []string{"status"}, found.Flags().Args())
+}
@@ -0,0 +1,368 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/AndriyBarskyi/gotrack/internal/tracker"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
+)
+
+type reportCmd struct {
+	sessionManager *tracker.SessionManager
+	groupBy        []string
+	from           string
+	to             string
+	task           string
+	project        string
+	tags           []string
+	percentiles    []float64
+	excludeRunning bool
+	profile        bool
+	compare        bool
+	focus          bool
+}
+
+// profileBarWidth is the width of the longest bar of a profile
+const profileBarWidth = 40
+
+// NewReportCmd creates a new report command
+func NewReportCmd(sm *tracker.SessionManager) *cobra.Command {
+	c := &reportCmd{
+		sessionManager: sm,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Report time spent, grouped and filtered as needed",
+		Long: `Report the number of sessions, the total, the average and percentiles of the
+time spent, grouped by any of day, week, month, task, tag, project, weekday and
+hour.
+
+Sessions that run across groups, e.g. across midnight when grouping by day,
+are split between them. Days given to --from and --to can be dates such as
+2024-03-31, today, yesterday or a number of days back such as -7; both days
+are included.
+
+With --profile, the time spent is charted per hour of the day and per day of
+the week instead, to show when the work gets done.
+
+With --compare, the time spent per task this week is compared with last week
+and with the average of the 4 weeks before, and this month with the same
+month last year. Periods in progress are compared with the same part of the
+earlier periods.
+
+With --focus, the sessions are walked in the order they were tracked to show
+the task switches per day, the average session length, the longest focus
+block on one task, allowing pauses of up to 5 minutes, and the fragmentation:
+the average number of distinct tasks in each tracked hour.`,
+		Example: `
+  gotrack report
+  gotrack report --by day --from -7
+  gotrack report --by project,tag --from 2024-01-01 --to 2024-03-31
+  gotrack report --by weekday --tag deep-work
+  gotrack report --by hour --project gotrack --percentiles 50,90,99
+  gotrack report --profile --tag deep-work --from -30
+  gotrack report --compare --project gotrack
+  gotrack report --focus --from -14
+`,
+		Args: cobra.NoArgs,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringSliceVar(&c.groupBy, "by", []string{string(analytics.GroupTask)}, "Dimensions to group by: day, week, month, task, tag, project, weekday or hour")
+	cmd.Flags().StringVar(&c.from, "from", "", "First day of the report")
+	cmd.Flags().StringVar(&c.to, "to", "", "Last day of the report")
+	cmd.Flags().StringVar(&c.task, "task", "", "Only report sessions of a task")
+	cmd.Flags().StringVar(&c.project, "project", "", "Only report sessions of a project")
+	cmd.Flags().StringArrayVar(&c.tags, "tag", nil, "Only report sessions with a tag, can be repeated")
+	cmd.Flags().Float64SliceVar(&c.percentiles, "percentiles", []float64{50, 90}, "Percentiles of the session durations to show")
+	cmd.Flags().BoolVar(&c.excludeRunning, "exclude-running", false, "Leave the running session out of the report")
+	cmd.Flags().BoolVar(&c.profile, "profile", false, "Chart the time spent per hour of the day and per day of the week")
+	cmd.Flags().BoolVar(&c.compare, "compare", false, "Compare the time spent per task with earlier weeks and months")
+	cmd.Flags().BoolVar(&c.focus, "focus", false, "Show task switches, session lengths, focus blocks and fragmentation per day")
+	cmd.MarkFlagsMutuallyExclusive("profile", "compare", "focus", "by")
+	cmd.MarkFlagsMutuallyExclusive("profile", "compare", "focus", "percentiles")
+	cmd.MarkFlagsMutuallyExclusive("compare", "from")
+	cmd.MarkFlagsMutuallyExclusive("compare", "to")
+
+	return cmd
+}
+
+func (c *reportCmd) run(cmd *cobra.Command, args []string) error {
+	sm := c.sessionManager
+	if sm == nil {
+		sm = GetSessionManager()
+		if sm == nil {
+			fmt.Println("No session manager available. Please ensure GoTrack is properly initialized.")
+			return fmt.Errorf("session manager not initialized")
+		}
+	}
+
+	query, err := c.query(time.Now())
+	if err != nil {
+		return err
+	}
+	for _, p := range c.percentiles {
+		if p < 0 || p > 100 {
+			return fmt.Errorf("percentile %g is not between 0 and 100", p)
+		}
+	}
+
+	ssns, err := sm.GetAllSessions()
+	if err != nil {
+		return fmt.Errorf("failed to get sessions: %v", err)
+	}
+
+	if c.compare {
+		trends := analytics.Trends(ssns, query)
+		if machineOutput() {
+			return writeOutput(newTrendsOutput(trends))
+		}
+		return writeTrends(os.Stdout, trends)
+	}
+
+	if c.profile {
+		profile := analytics.BuildProfile(ssns, query)
+		if machineOutput() {
+			return writeOutput(newProfileOutput(profile))
+		}
+		if profile.Total == 0 {
+			fmt.Println("No sessions found")
+			return nil
+		}
+		return writeProfile(os.Stdout, profile)
+	}
+
+	if c.focus {
+		focus := analytics.BuildFocus(ssns, query)
+		if machineOutput() {
+			return writeOutput(newFocusOutput(focus, query.Days))
+		}
+		if focus.Sessions == 0 {
+			fmt.Println("No sessions found")
+			return nil
+		}
+		return writeFocus(os.Stdout, focus, query.Days)
+	}
+
+	report := analytics.Run(ssns, query)
+	if machineOutput() {
+		return writeOutput(newReportOutput(report, c.percentiles))
+	}
+	if report.Total.Count == 0 {
+		fmt.Println("No sessions found")
+		return nil
+	}
+	return c.writeTable(os.Stdout, report)
+}
+
+// query builds the analytics query from the flags
+func (c *reportCmd) query(now time.Time) (analytics.Query, error) {
+	q := analytics.Query{
+		Task:           c.task,
+		Project:        c.project,
+		Tags:           c.tags,
+		ExcludeRunning: c.excludeRunning,
+		Days:           reportDays(),
+		Now:            now,
+	}
+
+	for _, name := range c.groupBy {
+		g, err := analytics.ParseGroupBy(name)
+		if err != nil {
+			return q, err
+		}
+		q.GroupBy = append(q.GroupBy, g)
+	}
+
+	if c.from != "" {
+		from, err := q.Days.ParseDay(c.from, now)
+		if err != nil {
+			return q, fmt.Errorf("--from: %v", err)
+		}
+		q.From = from
+	}
+	if c.to != "" {
+		to, err := q.Days.ParseDay(c.to, now)
+		if err != nil {
+			return q, fmt.Errorf("--to: %v", err)
+		}
+		q.To = q.Days.Next(to)
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && !q.To.After(q.From) {
+		return q, fmt.Errorf("--from %s is after --to %s", c.from, c.to)
+	}
+
+	return q, nil
+}
+
+// writeTable writes the report as a table with one row per group and a
+// final row with the totals
+func (c *reportCmd) writeTable(out io.Writer, report *analytics.Report) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	var header []string
+	for _, g := range report.GroupBy {
+		header = append(header, strings.ToUpper(string(g)))
+	}
+	header = append(header, "SESSIONS", "TOTAL", "AVERAGE")
+	for _, p := range c.percentiles {
+		header = append(header, "P"+strconv.FormatFloat(p, 'f', -1, 64))
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+
+	for _, row := range report.Rows {
+		fmt.Fprintln(w, strings.Join(c.rowCells(row.Keys, row), "\t"))
+	}
+
+	totalKeys := make([]string, len(report.GroupBy))
+	if len(totalKeys) > 0 {
+		totalKeys[0] = "TOTAL"
+	}
+	fmt.Fprintln(w, strings.Join(c.rowCells(totalKeys, report.Total), "\t"))
+
+	return w.Flush()
+}
+
+func (c *reportCmd) rowCells(keys []string, row analytics.Row) []string {
+	cells := append([]string{}, keys...)
+	cells = append(cells,
+		strconv.Itoa(row.Count),
+		formatDuration(row.Total),
+		formatDuration(row.Average()),
+	)
+	for _, p := range c.percentiles {
+		cells = append(cells, formatDuration(row.Percentile(p)))
+	}
+	return cells
+}
+
+// writeProfile charts the time spent per hour of the day and per day of the
+// week
+func writeProfile(out io.Writer, p *analytics.Profile) error {
+	labels := make([]string, 0, len(p.Hours)+len(p.Weekdays))
+	values := make([]time.Duration, 0, cap(labels))
+	for h, d := range p.Hours {
+		labels = append(labels, fmt.Sprintf("%02d:00", h))
+		values = append(values, d)
+	}
+	for wd, d := range p.Weekdays {
+		labels = append(labels, time.Weekday(wd).String())
+		values = append(values, d)
+	}
+
+	fmt.Fprintln(out, "Hour of day:")
+	writeBars(out, p, labels[:len(p.Hours)], values[:len(p.Hours)])
+	fmt.Fprintln(out, "\nDay of week:")
+	writeBars(out, p, labels[len(p.Hours):], values[len(p.Hours):])
+
+	fmt.Fprintf(out, "\nMost time is spent at %s and on %ss\n",
+		color.CyanString("%02d:00", p.PeakHour()), color.CyanString(p.PeakWeekday().String()))
+	return nil
+}
+
+// writeBars writes one bar per value, scaled to the largest value
+func writeBars(out io.Writer, p *analytics.Profile, labels []string, values []time.Duration) {
+	largest := slices.Max(values)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for i, d := range values {
+		filled := 0
+		if largest > 0 {
+			filled = int(math.Round(float64(d) / float64(largest) * profileBarWidth))
+		}
+		bar := color.GreenString(strings.Repeat("#", filled)) + strings.Repeat(" ", profileBarWidth-filled)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%3.0f%%\n", labels[i], bar, formatDuration(d), 100*p.Share(d))
+	}
+	w.Flush()
+}
+
+// writeFocus writes the focus metrics of every day and of all of them
+func writeFocus(out io.Writer, f *analytics.Focus, days analytics.Days) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DAY\tSESSIONS\tSWITCHES\tAVG SESSION\tLONGEST FOCUS\tTASKS/HOUR")
+	for _, d := range f.Days {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%.1f\n", days.Key(d.Day), d.Sessions, d.Switches,
+			formatDuration(d.AverageSession()), formatDuration(d.Longest.Duration), d.Fragmentation)
+	}
+	fmt.Fprintf(w, "TOTAL\t%d\t%d\t%s\t%s\t%.1f\n", f.Sessions, f.Switches,
+		formatDuration(f.AverageSession()), formatDuration(f.Longest.Duration), f.Fragmentation)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\n%.1f switches per day, sessions last %s on average\n",
+		f.SwitchesPerDay(), formatDuration(f.AverageSession()))
+	fmt.Fprintf(out, "Longest focus block: %s on %s from %s\n", formatDuration(f.Longest.Duration),
+		color.CyanString(f.Longest.Task), f.Longest.Start.In(days.Location).Format("Jan 2 15:04"))
+	return nil
+}
+
+// writeTrends writes one table per trend with the change of every task
+func writeTrends(out io.Writer, trends []analytics.Trend) error {
+	for i, t := range trends {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		previous := formatPeriod(t.Previous[0])
+		if n := len(t.Previous); n > 1 {
+			previous = "the same part of each week since " + t.Previous[n-1].From.Format("Jan 2")
+		}
+		fmt.Fprintf(out, "%s (%s vs %s)\n", trendTitle(t), formatPeriod(t.Current), previous)
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TASK\tCURRENT\tBEFORE\tCHANGE")
+		for _, tc := range t.Tasks {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", tc.Task, formatDuration(tc.Current), formatDuration(tc.Previous), formatChange(tc.Change))
+		}
+		fmt.Fprintf(w, "TOTAL\t%s\t%s\t%s\n", formatDuration(t.Total.Current), formatDuration(t.Total.Previous), formatChange(t.Total))
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func trendTitle(t analytics.Trend) string {
+	switch t.Name {
+	case analytics.TrendWeek:
+		return "This week vs last week"
+	case analytics.TrendMonthLastYear:
+		return "This month vs " + t.Previous[0].From.Format("January 2006")
+	case analytics.TrendRolling4Weeks:
+		return fmt.Sprintf("This week vs the average of the last %d weeks", len(t.Previous))
+	default:
+		return t.Name
+	}
+}
+
+func formatPeriod(p analytics.Period) string {
+	return p.From.Format("Jan 2") + " - " + p.To.Format("Jan 2 15:04")
+}
+
+// formatChange formats the delta of a change with its percentage, green for
+// more time spent and red for less
+func formatChange(c analytics.Change) string {
+	delta := c.Delta()
+	switch {
+	case delta == 0:
+		return "="
+	case c.Previous == 0:
+		return color.GreenString("+%s (new)", formatDuration(delta))
+	}
+	percent, _ := c.Percent()
+	if delta < 0 {
+		return color.RedString("-%s (%.0f%%)", formatDuration(-delta), percent)
+	}
+	return color.GreenString("+%s (+%.0f%%)", formatDuration(delta), percent)
+}
@@ -1,21 +1,39 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/AndriyBarskyi/gotrack/internal/config"
+	"github.com/AndriyBarskyi/gotrack/internal/hooks"
+	"github.com/AndriyBarskyi/gotrack/internal/models"
 	"github.com/AndriyBarskyi/gotrack/internal/storage"
 	"github.com/AndriyBarskyi/gotrack/internal/tracker"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
 )
 
 var (
+	dataDirFlag string
+	profileFlag string
+	outputFlag  string
+
+	paths          config.Paths
+	projectFile    string
 	appConfig      *config.Config
+	configErr      error
+	envOverrides   []string
 	sessionManager *tracker.SessionManager
 	sessionStorage storage.Storage
+	hookRunner     *hooks.Runner
+
+	interruptionStorage *storage.InterruptionStorage
+	pomodoroStorage     *storage.PomodoroStorage
+	pomodoroStatusPath  string
 )
 
 var rootCmd = &cobra.Command{
@@ -25,13 +43,26 @@ var rootCmd = &cobra.Command{
 
 Track your time with ease using simple commands. Get started by creating a new
 session with 'gotrack start' and stop it with 'gotrack stop'.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkOutputFormat(cmd); err != nil {
+			return err
+		}
+		if configErr != nil && cmd.Annotations[allowInvalidConfig] == "" {
+			cmd.SilenceUsage = true
+			return configErr
+		}
+		return nil
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
 	},
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	// Hooks run in the background, let them finish before exiting
+	hookRunner.Wait()
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -40,12 +71,23 @@ func Execute() {
 func init() {
 	cobra.OnInitialize(initConfig)
 
+	rootCmd.PersistentFlags().StringVar(&dataDirFlag, "data-dir", "", "Directory for the config and data files (default $GOTRACK_HOME, ~/.gotrack or the XDG directories)")
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "Named profile with its own config and data (default $GOTRACK_PROFILE)")
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", outputText, "Output format: text, json, yaml or csv")
+
 	rootCmd.AddCommand(NewStartCmd(nil))
 	rootCmd.AddCommand(NewStopCmd(nil))
 	rootCmd.AddCommand(NewShowCmd(nil))
+	rootCmd.AddCommand(NewReportCmd(nil))
+	rootCmd.AddCommand(NewHeatmapCmd(nil))
+	rootCmd.AddCommand(NewBudgetCmd(nil))
+	rootCmd.AddCommand(NewScoreCmd(nil))
+	rootCmd.AddCommand(NewTimesheetCmd(nil))
+	rootCmd.AddCommand(NewStandupCmd(nil))
 	rootCmd.AddCommand(NewCurrentCmd(nil))
 	rootCmd.AddCommand(NewPomoCmd(nil))
 	rootCmd.AddCommand(NewStatusCmd(nil))
+	rootCmd.AddCommand(NewConfigCmd())
 }
 
 // GetSessionManager returns the initialized session manager
@@ -53,32 +95,132 @@ func GetSessionManager() *tracker.SessionManager {
 	return sessionManager
 }
 
+// newSession returns the template of a new session for the task, with the
+// project and tags of the configuration. Without a task, the task template
+// of the project is used.
+func newSession(task string) (models.Session, error) {
+	if task == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return models.Session{}, err
+		}
+		if projectFile != "" {
+			dir = filepath.Dir(projectFile)
+		}
+		task = appConfig.Session.TaskName(dir, time.Now())
+	}
+	switch {
+	case task == "" && appConfig.Session.Task != "":
+		return models.Session{}, fmt.Errorf("no task given and the session.task template %q gives an empty task name here", appConfig.Session.Task)
+	case task == "":
+		return models.Session{}, fmt.Errorf("no task given and no session.task template in %s", config.ProjectFileName)
+	}
+
+	return models.Session{
+		Task:    task,
+		Project: appConfig.Session.Project,
+		Tags:    appConfig.Session.Tags,
+	}, nil
+}
+
+// reportDays returns the day boundaries statistics are computed with
+func reportDays() analytics.Days {
+	loc, err := appConfig.Reports.Location()
+	if err != nil {
+		loc = time.Local
+	}
+	return analytics.Days{Location: loc, StartHour: appConfig.Reports.DayStartHour}
+}
+
+// fireHook runs the hooks configured for the event, if any
+func fireHook(name, task string, data map[string]string) {
+	hookRunner.Fire(hooks.Event{
+		Name: name,
+		Time: time.Now(),
+		Task: task,
+		Data: data,
+	})
+}
+
 // initConfig loads the application configuration
 func initConfig() {
 	var err error
-	appConfig, err = config.Load("")
+	paths, err = config.ResolvePaths(dataDirFlag, profileFlag)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error resolving GoTrack directories: %v\n", err)
 		os.Exit(1)
 	}
 
-	homeDir, err := os.UserHomeDir()
+	appConfig, err = loadConfig(paths.ConfigFile)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting user home directory: %v\n", err)
-		os.Exit(1)
+		var verr *config.ValidationError
+		if !errors.As(err, &verr) {
+			fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+			os.Exit(1)
+		}
+		// Commands refuse to run on an invalid config, except the ones that
+		// help fixing it
+		configErr = err
+		appConfig = config.Default()
 	}
 
-	gotrackDir := filepath.Join(homeDir, ".gotrack")
-	if err := os.MkdirAll(gotrackDir, 0755); err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating .gotrack directory: %v\n", err)
+	dataDir := paths.DataDir
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating data directory: %v\n", err)
 		os.Exit(1)
 	}
 
-	sessionStorage, err = storage.NewFileStorage(filepath.Join(gotrackDir, "sessions.jsonl"))
+	sessionStorage, err = storage.NewFileStorage(filepath.Join(dataDir, "sessions.jsonl"))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error initializing storage: %v\n", err)
 		os.Exit(1)
 	}
 
 	sessionManager = tracker.NewSessionManager(sessionStorage)
+
+	interruptionStorage, err = storage.NewInterruptionStorage(filepath.Join(dataDir, "interruptions.jsonl"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error initializing interruption storage: %v\n", err)
+		os.Exit(1)
+	}
+	pomodoroStorage, err = storage.NewPomodoroStorage(filepath.Join(dataDir, "pomodoros.jsonl"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error initializing pomodoro storage: %v\n", err)
+		os.Exit(1)
+	}
+	pomodoroStatusPath = filepath.Join(dataDir, "pomodoro.json")
+
+	hookRunner = hooks.NewRunner(appConfig.Hooks, os.Stderr)
+}
+
+// loadConfig loads the config file, layers the .gotrack.yaml file of the
+// project in the working directory over it and applies the GOTRACK_*
+// environment overrides on top
+func loadConfig(path string) (*config.Config, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if wd, err := os.Getwd(); err == nil {
+		if projectFile, err = config.FindProjectFile(wd); err != nil {
+			return nil, err
+		}
+	}
+	if projectFile != "" {
+		if err := cfg.ApplyProjectFile(projectFile); err != nil {
+			return nil, err
+		}
+	}
+
+	envOverrides, err = cfg.ApplyEnv(os.Environ())
+	if err != nil {
+		return nil, fmt.Errorf("invalid environment override:\n%w", err)
+	}
+	if len(envOverrides) > 0 {
+		if err := cfg.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid environment override:\n%w", err)
+		}
+	}
+	return cfg, nil
 }
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/AndriyBarskyi/gotrack/internal/tracker"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
+)
+
+const (
+	// defaultScoreWeeks is the number of weeks of score history shown
+	defaultScoreWeeks = 12
+	// scoreBarWidth is the width of the bar of a full score
+	scoreBarWidth = 40
+)
+
+type scoreCmd struct {
+	sessionManager *tracker.SessionManager
+	explain        bool
+	window         int
+	weeks          int
+}
+
+// NewScoreCmd creates a new score command
+func NewScoreCmd(sm *tracker.SessionManager) *cobra.Command {
+	c := &scoreCmd{
+		sessionManager: sm,
+	}
+	cmd := &cobra.Command{
+		Use:   "score",
+		Short: "Show the productivity score",
+		Long: `Show the productivity score, from 0 to 100, over the last days.
+
+The score adds up, by weight, how close three components come to their
+targets within the window:
+  volume       the time tracked
+  consistency  the number of days with tracked time
+  streak       the longest run of consecutive tracked days
+
+The window, the targets and the weights are set under reports.score in the
+config file. --explain shows the part of every component, what would raise
+the score the most and the score at the end of each of the last weeks.`,
+		Example: `
+  gotrack score
+  gotrack score --explain
+  gotrack score --window 7
+  gotrack score --explain --weeks 26 --output json
+`,
+		Args: cobra.NoArgs,
+		RunE: c.run,
+	}
+
+	cmd.Flags().BoolVar(&c.explain, "explain", false, "Break the score into its components and show its history")
+	cmd.Flags().IntVar(&c.window, "window", 0, "Number of days the score looks at (default reports.score.window_days)")
+	cmd.Flags().IntVar(&c.weeks, "weeks", defaultScoreWeeks, "Number of weeks of history shown with --explain")
+
+	return cmd
+}
+
+func (c *scoreCmd) run(cmd *cobra.Command, args []string) error {
+	sm := c.sessionManager
+	if sm == nil {
+		sm = GetSessionManager()
+		if sm == nil {
+			fmt.Println("No session manager available. Please ensure GoTrack is properly initialized.")
+			return fmt.Errorf("session manager not initialized")
+		}
+	}
+	if cmd.Flags().Changed("window") && c.window < 1 {
+		return fmt.Errorf("--window must be at least 1, got %d", c.window)
+	}
+	if c.weeks < 1 {
+		return fmt.Errorf("--weeks must be at least 1, got %d", c.weeks)
+	}
+
+	ssns, err := sm.GetAllSessions()
+	if err != nil {
+		return fmt.Errorf("failed to get sessions: %v", err)
+	}
+
+	settings := scoreSettings()
+	if c.window > 0 {
+		settings.WindowDays = c.window
+	}
+	days, now := reportDays(), time.Now()
+	score := analytics.CalculateScore(ssns, settings, days, now)
+	history := analytics.ScoreHistory(ssns, settings, days, now, c.weeks, 7)
+
+	if machineOutput() {
+		return writeOutput(newScoreOutput(score, settings, history, days))
+	}
+
+	fmt.Printf("Productivity score: %s (last %d days, %s - %s)\n",
+		color.CyanString("%.1f/%.0f", score.Total, analytics.MaxScore), settings.WindowDays,
+		score.Window.From.Format("Jan 2"), score.Window.To.Add(-time.Nanosecond).Format("Jan 2"))
+	if !c.explain {
+		return nil
+	}
+	fmt.Println()
+	return writeScore(os.Stdout, score, history)
+}
+
+// writeScore writes the components of the score, the one to work on and
+// the history of the score
+func writeScore(out io.Writer, score analytics.Score, history []analytics.ScorePoint) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COMPONENT\tREACHED\tTARGET\tWEIGHT\tPOINTS")
+	for _, c := range score.Components {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%.0f%%\t%.1f/%.1f\n",
+			c.Name, formatScoreValue(c.Value, c.Unit), formatScoreValue(c.Target, c.Unit),
+			100*c.Weight, c.Points, c.Weight*analytics.MaxScore)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out)
+	if c, ok := score.Weakest(); ok {
+		fmt.Fprintf(out, "To gain the most: %s (+%.1f points)\n", scoreAdvice(c), c.Missing())
+	} else {
+		fmt.Fprintln(out, "Every target is met")
+	}
+
+	fmt.Fprintln(out, "\nScore at the end of each week:")
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, p := range history {
+		filled := int(math.Round(p.Score / analytics.MaxScore * scoreBarWidth))
+		bar := color.GreenString(strings.Repeat("#", filled)) + strings.Repeat(" ", scoreBarWidth-filled)
+		fmt.Fprintf(w, "%s\t%s\t%5.1f\n", p.Day.Format("Jan 2"), bar, p.Score)
+	}
+	return w.Flush()
+}
+
+// scoreAdvice says what it takes for a component to reach its target
+func scoreAdvice(c analytics.ScoreComponent) string {
+	switch c.Name {
+	case analytics.ScoreVolume:
+		missing := time.Duration((c.Target - c.Value) * float64(time.Hour))
+		return fmt.Sprintf("track %s more to reach the volume target", formatDuration(missing))
+	case analytics.ScoreConsistency:
+		return fmt.Sprintf("track time on %.0f more days to reach the consistency target", c.Target-c.Value)
+	default:
+		return fmt.Sprintf("keep a streak of %.0f days to reach the streak target", c.Target)
+	}
+}
+
+func formatScoreValue(v float64, unit string) string {
+	if unit == "hours" {
+		return formatDuration(time.Duration(v * float64(time.Hour)))
+	}
+	return fmt.Sprintf("%.0f days", v)
+}
+
+// scoreSettings returns the productivity score settings of the config
+func scoreSettings() analytics.ScoreSettings {
+	sc := appConfig.Reports.Score
+	return analytics.ScoreSettings{
+		WindowDays:        sc.WindowDays,
+		VolumeTarget:      time.Duration(sc.VolumeTarget),
+		ActiveDaysTarget:  sc.ActiveDaysTarget,
+		StreakTarget:      sc.StreakTarget,
+		VolumeWeight:      sc.Weights.Volume,
+		ConsistencyWeight: sc.Weights.Consistency,
+		StreakWeight:      sc.Weights.Streak,
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
@@ -24,6 +25,9 @@ type showCmd struct {
 	yearly         bool
 	all            bool
 	top            bool
+	interruptions  bool
+	pomodoro       bool
+	excludeRunning bool
 }
 
 // NewShowCmd creates a new show command
@@ -46,6 +50,9 @@ func NewShowCmd(sm *tracker.SessionManager) *cobra.Command {
   gotrack show --monthly
   gotrack show --all
   gotrack show --top
+  gotrack show --interruptions
+  gotrack show --pomodoro
+  gotrack show --exclude-running
 `,
 		Args: cobra.MaximumNArgs(1),
 		RunE: c.run,
@@ -58,6 +65,9 @@ func NewShowCmd(sm *tracker.SessionManager) *cobra.Command {
 	cmd.Flags().BoolVarP(&c.yearly, "yearly", "y", false, "Show yearly statistics")
 	cmd.Flags().BoolVar(&c.all, "all", false, "Show comprehensive statistics")
 	cmd.Flags().BoolVar(&c.top, "top", false, "Show top tasks by time spent")
+	cmd.Flags().BoolVar(&c.interruptions, "interruptions", false, "Show Pomodoro interruptions per day and per task")
+	cmd.Flags().BoolVar(&c.pomodoro, "pomodoro", false, "Show Pomodoro statistics and progress toward the daily goal")
+	cmd.Flags().BoolVar(&c.excludeRunning, "exclude-running", false, "Leave the running session out of the statistics")
 
 	return cmd
 }
@@ -76,7 +86,8 @@ func (c *showCmd) run(cmd *cobra.Command, args []string) error {
 	var err error
 
 	if c.today {
-		ssns, err = sm.GetTodaySessions()
+		days, now := reportDays(), time.Now()
+		ssns, err = sm.GetTodaySessions(days.Start(now), days.Next(now))
 	} else if c.task != "" {
 		ssns, err = sm.GetSessionsForTask(c.task)
 	} else {
@@ -86,6 +97,13 @@ func (c *showCmd) run(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get sessions: %v", err)
 	}
+	if c.excludeRunning {
+		ssns = analytics.Finished(ssns)
+	}
+
+	if machineOutput() {
+		return c.writeStats(ssns)
+	}
 
 	if len(args) > 0 {
 		_, err := fmt.Sscanf(args[0], "%d", &c.amount)
@@ -103,41 +121,80 @@ func (c *showCmd) run(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(ssns) > 0 {
-		todayDuration := analytics.CalculateTodayDuration(ssns, c.task)
-		totalDuration := analytics.CalculateTotalDuration(ssns, c.task)
+		days := reportDays()
+		finished := analytics.Finished(ssns)
+		// live formats a duration and marks the part of it that comes from
+		// the running session
+		live := func(calc func([]models.Session) time.Duration) string {
+			total := calc(ssns)
+			return formatLiveDuration(total, total-calc(finished))
+		}
+
+		todayDuration := live(func(ssns []models.Session) time.Duration {
+			return analytics.CalculateTodayDuration(ssns, c.task, days)
+		})
+		totalDuration := live(func(ssns []models.Session) time.Duration {
+			return analytics.CalculateTotalDuration(ssns, c.task)
+		})
 
 		if c.task != "" {
 			fmt.Printf("Today duration for task %s: %s\n",
 				color.CyanString(c.task),
-				formatDuration(todayDuration))
+				todayDuration)
 			fmt.Printf("Total duration for task %s: %s\n",
 				color.CyanString(c.task),
-				formatDuration(totalDuration))
+				totalDuration)
 		} else {
-			fmt.Printf("Today duration: %s\n", formatDuration(todayDuration))
-			fmt.Printf("Total duration: %s\n", formatDuration(totalDuration))
+			fmt.Printf("Today duration: %s\n", todayDuration)
+			fmt.Printf("Total duration: %s\n", totalDuration)
+
+			// Trends compare with earlier periods, which --today leaves out
+			// of ssns
+			trends := make(map[string]analytics.Trend)
+			if c.weekly || c.monthly || c.all {
+				all, err := sm.GetAllSessions()
+				if err != nil {
+					return fmt.Errorf("failed to get sessions: %v", err)
+				}
+				for _, trend := range analytics.Trends(all, analytics.Query{Days: days, ExcludeRunning: c.excludeRunning}) {
+					trends[trend.Name] = trend
+				}
+			}
 
 			if c.weekly || c.all {
-				weeklyDuration := analytics.CalculateWeeklyDuration(ssns, c.task)
-				fmt.Printf("Weekly duration: %s\n", formatDuration(weeklyDuration))
+				weeklyDuration := live(func(ssns []models.Session) time.Duration {
+					return analytics.CalculateWeeklyDuration(ssns, c.task, days)
+				})
+				fmt.Printf("Weekly duration: %s\n", weeklyDuration)
+				if trend, ok := trends[analytics.TrendWeek]; ok {
+					fmt.Printf("Compared to last week so far: %s\n", formatChange(trend.Total))
+				}
 			}
 
 			if c.monthly || c.all {
-				monthlyDuration := analytics.CalculateMonthlyDuration(ssns, c.task)
-				fmt.Printf("Monthly duration: %s\n", formatDuration(monthlyDuration))
+				monthlyDuration := live(func(ssns []models.Session) time.Duration {
+					return analytics.CalculateMonthlyDuration(ssns, c.task, days)
+				})
+				fmt.Printf("Monthly duration: %s\n", monthlyDuration)
+				if trend, ok := trends[analytics.TrendMonthLastYear]; ok && len(trend.Previous) > 0 {
+					fmt.Printf("Compared to %s so far: %s\n",
+						trend.Previous[0].From.Format("January 2006"), formatChange(trend.Total))
+				}
 			}
 
 			if c.yearly || c.all {
-				yearlyDuration := analytics.CalculateYearlyDuration(ssns, c.task)
-				fmt.Printf("Yearly duration: %s\n", formatDuration(yearlyDuration))
+				yearlyDuration := live(func(ssns []models.Session) time.Duration {
+					return analytics.CalculateYearlyDuration(ssns, c.task, days)
+				})
+				fmt.Printf("Yearly duration: %s\n", yearlyDuration)
 			}
 		}
 
-		fmt.Printf("Consecutive days: %d\n", analytics.CalculateConsecutiveDays(ssns))
+		fmt.Printf("Consecutive days: %d\n", analytics.CalculateConsecutiveDays(ssns, days))
 
 		if c.all {
-			longestStreak := analytics.CalculateLongestStreak(ssns)
-			productivityScore := analytics.GetProductivityScore(ssns)
+			longestStreak := analytics.CalculateLongestStreak(ssns, days)
+			productivityScore := analytics.CalculateScore(ssns, scoreSettings(), days, time.Now()).Total
 			fmt.Printf("Longest streak: %d days\n", longestStreak)
 			fmt.Printf("Productivity score: %.1f/100\n", productivityScore)
 		}
@@ -146,18 +203,165 @@ func (c *showCmd) run(cmd *cobra.Command, args []string) error {
 			fmt.Println("\nTop Tasks:")
 			topTasks := analytics.GetTopTasks(ssns, 5)
 			for i, task := range topTasks {
-				fmt.Printf("%d. %s: %s\n", i+1,
+				running := ""
+				if last := ssns[len(ssns)-1]; last.IsActive() && last.Task == task.Task {
+					running = color.YellowString(" (running)")
+				}
+				fmt.Printf("%d. %s: %s%s\n", i+1,
 					color.CyanString(task.Task),
-					formatDuration(task.Duration))
+					formatDuration(task.Duration),
+					running)
 			}
 		}
 	} else {
 		fmt.Println("No sessions found")
 	}
 
+	if c.interruptions || c.all {
+		if err := c.showInterruptions(); err != nil {
+			return err
+		}
+	}
+
+	if c.pomodoro || c.all {
+		if err := c.showPomodoros(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// writeStats writes the sessions with every statistic in the format selected
+// with --output
+func (c *showCmd) writeStats(ssns []models.Session) error {
+	if pomodoroStorage == nil || interruptionStorage == nil {
+		return fmt.Errorf("pomodoro storage not initialized")
+	}
+	poms, err := pomodoroStorage.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to get pomodoros: %v", err)
+	}
+	ints, err := interruptionStorage.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to get interruptions: %v", err)
+	}
+
+	out := newStatsOutput(ssns, poms, ints, c.task, appConfig.Pomodoro.DailyGoal, scoreSettings(), reportDays(), time.Now())
+	return writeOutput(out)
+}
+
+func (c *showCmd) showInterruptions() error {
+	if interruptionStorage == nil {
+		return fmt.Errorf("interruption storage not initialized")
+	}
+
+	ints, err := interruptionStorage.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to get interruptions: %v", err)
+	}
+	if c.task != "" {
+		var filtered []models.Interruption
+		for _, i := range ints {
+			if i.Task == c.task {
+				filtered = append(filtered, i)
+			}
+		}
+		ints = filtered
+	}
+
+	fmt.Println("\nInterruptions per day:")
+	if len(ints) == 0 {
+		fmt.Println("No interruptions recorded")
+		return nil
+	}
+
+	perDay := analytics.InterruptionsPerDay(ints, "", reportDays())
+	if len(perDay) > defaultAmount {
+		perDay = perDay[len(perDay)-defaultAmount:]
+	}
+	for _, day := range perDay {
+		fmt.Printf("%s: %d internal, %d external, %d voided\n",
+			day.Key, day.Internal, day.External, day.Voided)
+	}
+
+	fmt.Println("\nInterruptions per task:")
+	for _, task := range analytics.InterruptionsPerTask(ints) {
+		fmt.Printf("%s: %d internal, %d external, %d voided\n",
+			color.CyanString(task.Key), task.Internal, task.External, task.Voided)
+	}
+	return nil
+}
+
+func (c *showCmd) showPomodoros() error {
+	if pomodoroStorage == nil || interruptionStorage == nil {
+		return fmt.Errorf("pomodoro storage not initialized")
+	}
+
+	poms, err := pomodoroStorage.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to get pomodoros: %v", err)
+	}
+	ints, err := interruptionStorage.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to get interruptions: %v", err)
+	}
+
+	fmt.Println("\nPomodoros:")
+	days := reportDays()
+	perDay := analytics.PomodorosPerDay(poms, c.task, days)
+	if len(perDay) == 0 {
+		fmt.Println("No pomodoros recorded")
+		return nil
+	}
+
+	goal := appConfig.Pomodoro.DailyGoal
+	now := time.Now()
+	today := 0
+	if last := perDay[len(perDay)-1]; last.Day == days.Key(now) {
+		today = last.Completed
+	}
+
+	if goal > 0 {
+		fmt.Printf("Today: %d/%d %s\n", today, goal, goalProgressBar(today, goal))
+		current, longest := analytics.PomodoroGoalStreaks(perDay, goal, days, now)
+		fmt.Printf("Goal streak: %d days (longest %d days)\n", current, longest)
+	} else {
+		fmt.Printf("Today: %d\n", today)
+	}
+	fmt.Printf("Completion rate: %.0f%%\n", analytics.PomodoroCompletionRate(poms, c.task)*100)
+	fmt.Printf("Average before first interruption: %.1f\n",
+		analytics.AveragePomodorosBeforeInterruption(poms, ints, c.task, days))
+
+	fmt.Println("\nPomodoros per day:")
+	if len(perDay) > defaultAmount {
+		perDay = perDay[len(perDay)-defaultAmount:]
+	}
+	for _, day := range perDay {
+		line := fmt.Sprintf("%s: %d completed, %d abandoned", day.Day, day.Completed, day.Abandoned)
+		if goal > 0 && day.Completed >= goal {
+			line += " " + color.GreenString("goal reached")
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// goalProgressBar renders progress toward the daily goal, one cell per pomodoro
+func goalProgressBar(done, goal int) string {
+	filled := min(done, goal)
+	return "[" + color.GreenString(strings.Repeat("#", filled)) + strings.Repeat("-", goal-filled) + "]"
+}
+
+// formatLiveDuration formats a duration that includes live time elapsed in
+// the running session
+func formatLiveDuration(d, live time.Duration) string {
+	if live <= 0 {
+		return formatDuration(d)
+	}
+	return formatDuration(d) + color.YellowString(" (%s running)", formatDuration(live))
+}
+
 func formatDuration(d time.Duration) string {
 	hours := int(d.Hours())
 	minutes := int(d.Minutes()) % 60
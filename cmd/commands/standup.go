@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AndriyBarskyi/gotrack/internal/tracker"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
+)
+
+type standupCmd struct {
+	sessionManager *tracker.SessionManager
+	markdown       bool
+}
+
+// NewStandupCmd creates a new standup command
+func NewStandupCmd(sm *tracker.SessionManager) *cobra.Command {
+	c := &standupCmd{
+		sessionManager: sm,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "standup",
+		Short: "Summarize the previous working day and today for a standup",
+		Long: `Summarize the tasks worked on during the previous working day and today, with
+their durations, tags and notes, grouped per project, ready to paste into
+chat.
+
+The previous working day skips weekends and holidays, set under standup in
+the config file:
+
+  standup:
+    working_days: [monday, tuesday, wednesday, thursday, friday]
+    holidays: ["2026-12-24", "2026-12-25"]
+
+Notes are added to sessions with start --note and stop --note.`,
+		Example: `
+  gotrack standup
+  gotrack standup --markdown
+  gotrack standup --output json
+`,
+		Args: cobra.NoArgs,
+		RunE: c.run,
+	}
+
+	cmd.Flags().BoolVar(&c.markdown, "markdown", false, "Write the summary in Markdown")
+
+	return cmd
+}
+
+func (c *standupCmd) run(cmd *cobra.Command, args []string) error {
+	sm := c.sessionManager
+	if sm == nil {
+		sm = GetSessionManager()
+		if sm == nil {
+			fmt.Println("No session manager available. Please ensure GoTrack is properly initialized.")
+			return fmt.Errorf("session manager not initialized")
+		}
+	}
+	if c.markdown && machineOutput() {
+		return fmt.Errorf("--markdown cannot be combined with --output %s", outputFlag)
+	}
+
+	ssns, err := sm.GetAllSessions()
+	if err != nil {
+		return fmt.Errorf("failed to get sessions: %v", err)
+	}
+
+	days, now := reportDays(), time.Now()
+	working := analytics.WorkingDays{
+		Weekdays: appConfig.Standup.Weekdays(),
+		Holidays: appConfig.Standup.Holidays,
+	}
+	standup := []analytics.StandupDay{
+		analytics.BuildStandupDay(ssns, days, days.PreviousWorkingDay(now, working), now),
+		analytics.BuildStandupDay(ssns, days, days.Start(now), now),
+	}
+
+	if machineOutput() {
+		return writeOutput(newStandupOutput(standup, days))
+	}
+	return writeStandup(os.Stdout, standup, days, now, c.markdown)
+}
+
+// writeStandup writes the work of every day per project, in plain text or
+// in Markdown
+func writeStandup(out io.Writer, standup []analytics.StandupDay, days analytics.Days, now time.Time, markdown bool) error {
+	bold := func(s string) string { return s }
+	if markdown {
+		bold = func(s string) string { return "**" + s + "**" }
+	}
+
+	for i, day := range standup {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		title := bold(standupTitle(day.Day, days, now))
+		if len(day.Projects) == 0 {
+			fmt.Fprintf(out, "%s\nNothing tracked\n", title)
+			continue
+		}
+		fmt.Fprintf(out, "%s: %s\n", title, formatChatDuration(day.Total))
+
+		for _, p := range day.Projects {
+			name := p.Project
+			if name == "" {
+				name = "No project"
+			}
+			fmt.Fprintf(out, "- %s: %s\n", bold(name), formatChatDuration(p.Total))
+			for _, t := range p.Tasks {
+				fmt.Fprintf(out, "  - %s\n", standupTask(t, markdown))
+			}
+		}
+	}
+	return nil
+}
+
+// standupTitle names a day with its date, and as today or yesterday when
+// it is one of them
+func standupTitle(day time.Time, days analytics.Days, now time.Time) string {
+	date := day.Format("Monday, Jan 2")
+	today := days.Start(now)
+	switch {
+	case day.Equal(today):
+		return "Today, " + date
+	case days.Next(day).Equal(today):
+		return "Yesterday, " + date
+	default:
+		return date
+	}
+}
+
+// standupTask formats a task with its duration, tags and notes
+func standupTask(t analytics.StandupTask, markdown bool) string {
+	var b strings.Builder
+	b.WriteString(t.Task + ", " + formatChatDuration(t.Duration))
+	if t.Running {
+		b.WriteString(" so far")
+	}
+	for _, tag := range t.Tags {
+		if markdown {
+			b.WriteString(" `#" + tag + "`")
+		} else {
+			b.WriteString(" #" + tag)
+		}
+	}
+	if len(t.Notes) > 0 {
+		b.WriteString(": " + strings.Join(t.Notes, "; "))
+	}
+	return b.String()
+}
+
+// formatChatDuration formats a duration to the minute the way people write
+// it, e.g. 2h 30m
+func formatChatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	switch {
+	case minutes < 60:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	default:
+		return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
+	}
+}
@@ -2,17 +2,18 @@ package cmd
 
 import (
 	"fmt"
-	"os"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
-	"github.com/AndriyBarskyi/gotrack/internal/storage"
+	"github.com/AndriyBarskyi/gotrack/internal/hooks"
 	"github.com/AndriyBarskyi/gotrack/internal/tracker"
 )
 
 type startCmd struct {
 	sessionManager *tracker.SessionManager
+	note           string
 }
 
 // NewStartCmd creates a new start command
@@ -20,15 +21,25 @@ func NewStartCmd(sm *tracker.SessionManager) *cobra.Command {
 	c := &startCmd{
 		sessionManager: sm,
 	}
-	return &cobra.Command{
-		Use:   "start <task name>",
+	cmd := &cobra.Command{
+		Use:   "start [task name]",
 		Short: "Start tracking a task",
-		Long:  `Start tracking time for a specific task. This will create a new session.`,
+		Long: `Start tracking time for a specific task. This will create a new session.
+
+Inside a project with a .gotrack.yaml file, the session gets the project and
+tags set there, and the task can be left out to use the session.task template
+of the project.`,
 		Example: `  gotrack start "Working on feature X"
-  gotrack start "Meeting with team"`,
-		Args: cobra.ExactArgs(1),
+  gotrack start "Meeting with team"
+  gotrack start --note "Fix the flaky test"
+  gotrack start`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: c.run,
 	}
+
+	cmd.Flags().StringVar(&c.note, "note", "", "Note on what the session is about")
+
+	return cmd
 }
 
 func (c *startCmd) run(cmd *cobra.Command, args []string) error {
@@ -41,25 +52,36 @@ func (c *startCmd) run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	session, err := sm.Start(args[0])
+	task := ""
+	if len(args) > 0 {
+		task = args[0]
+	}
+	template, err := newSession(task)
+	if err != nil {
+		return err
+	}
+	template.Note = c.note
+
+	session, err := sm.StartSession(template)
 	if err != nil {
 		return fmt.Errorf("failed to start session: %v", err)
 	}
 
-	fmt.Printf("Started tracking %s at %s\n",
+	fireHook(hooks.SessionStarted, session.Task, nil)
+	defer warnBudgets(sm, session)
+
+	if machineOutput() {
+		return writeOutput(newSingleSessionOutput(session, time.Now()))
+	}
+
+	project := ""
+	if session.Project != "" {
+		project = " in " + color.CyanString(session.Project)
+	}
+	fmt.Printf("Started tracking %s%s at %s\n",
 		color.CyanString(session.Task),
+		project,
 		session.StartTime.Format("15:04:05"),
 	)
 	return nil
 }
-
-func init() {
-	storage, err := storage.NewFileStorage("~/.gotrack/sessions.jsonl")
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error initializing storage: %v\n", err)
-		os.Exit(1)
-	}
-	sessionManager := tracker.NewSessionManager(storage)
-
-	rootCmd.AddCommand(NewStartCmd(sessionManager))
-}
@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/AndriyBarskyi/gotrack/internal/models"
 	"github.com/AndriyBarskyi/gotrack/internal/tracker"
 )
 
@@ -37,10 +40,17 @@ func (c *statusCmd) run(cmd *cobra.Command, args []string) error {
 	}
 
 	session, err := sm.GetLast()
+	if errors.Is(err, models.ErrNoSessions) {
+		session, err = nil, nil
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get last session: %v", err)
 	}
 
+	if machineOutput() {
+		return writeOutput(newSingleSessionOutput(session, time.Now()))
+	}
+
 	if session == nil {
 		fmt.Println("No sessions found")
 		return nil
@@ -7,11 +7,13 @@ import (
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
+	"github.com/AndriyBarskyi/gotrack/internal/hooks"
 	"github.com/AndriyBarskyi/gotrack/internal/tracker"
 )
 
 type stopCmd struct {
 	sessionManager *tracker.SessionManager
+	note           string
 }
 
 // NewStopCmd creates a new stop command
@@ -19,14 +21,19 @@ func NewStopCmd(sm *tracker.SessionManager) *cobra.Command {
 	c := &stopCmd{
 		sessionManager: sm,
 	}
-	return &cobra.Command{
-		Use:     "stop",
-		Short:   "Stop tracking the current task",
-		Long:    `Stop tracking the currently running task and record the end time.`,
-		Example: `  gotrack stop`,
-		Args:    cobra.NoArgs,
-		RunE:    c.run,
+	cmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stop tracking the current task",
+		Long:  `Stop tracking the currently running task and record the end time.`,
+		Example: `  gotrack stop
+  gotrack stop --note "Reviewed the budget PR"`,
+		Args: cobra.NoArgs,
+		RunE: c.run,
 	}
+
+	cmd.Flags().StringVar(&c.note, "note", "", "Note on what was done, added after the note given to start")
+
+	return cmd
 }
 
 func (c *stopCmd) run(cmd *cobra.Command, args []string) error {
@@ -48,12 +55,21 @@ func (c *stopCmd) run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no active session to stop")
 	}
 
-	session, err := sm.Finish()
+	session, err := sm.FinishWithNote(c.note)
 	if err != nil {
 		return fmt.Errorf("failed to stop session: %v", err)
 	}
 
 	duration := session.EndTime.Sub(session.StartTime).Round(time.Second)
+	fireHook(hooks.SessionFinished, session.Task, map[string]string{
+		"duration": duration.String(),
+	})
+	defer warnBudgets(sm, session)
+
+	if machineOutput() {
+		return writeOutput(newSingleSessionOutput(session, time.Now()))
+	}
+
 	hours := int(duration.Hours())
 	minutes := int(duration.Minutes()) % 60
 	seconds := int(duration.Seconds()) % 60
@@ -62,6 +78,5 @@ func (c *stopCmd) run(cmd *cobra.Command, args []string) error {
 		color.CyanString(session.Task),
 		hours, minutes, seconds,
 	)
-
 	return nil
 }
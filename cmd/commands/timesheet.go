@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	cfg "github.com/AndriyBarskyi/gotrack/internal/config"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
+)
+
+type timesheetCmd struct {
+	sessionManager *tracker.SessionManager
+	week           string
+	by             string
+	task           string
+	project        string
+	tags           []string
+	rounding       string
+	increment      cfg.Duration
+	roundPer       string
+	minimum        cfg.Duration
+	markdown       bool
+}
+
+// NewTimesheetCmd creates a new timesheet command
+func NewTimesheetCmd(sm *tracker.SessionManager) *cobra.Command {
+	defaults := cfg.Default().Timesheet
+	c := &timesheetCmd{
+		sessionManager: sm,
+		rounding:       defaults.Rounding,
+		increment:      defaults.Increment,
+		roundPer:       defaults.RoundPer,
+		minimum:        defaults.Minimum,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "timesheet",
+		Short: "Show the time per task or project on each day of a week",
+		Long: `Show a timesheet with the time spent per task or project on each day of an
+ISO week, Monday to Sunday, with daily and weekly totals.
+
+For billing, the time can be rounded to the nearest, up or down to 6, 15 or
+30 minutes, either every session or the time of each task or project per day,
+and raised to a minimum for every entry with tracked time. The rounding is
+set under timesheet in the config file and can be overridden with flags:
+
+  timesheet:
+    rounding: up
+    increment: 15m
+    round_per: day
+    minimum: 15m
+
+--markdown writes the timesheet as a Markdown table and --output csv as CSV.`,
+		Example: `
+  gotrack timesheet
+  gotrack timesheet --week 2026-W41 --by project
+  gotrack timesheet --week last --project client-a --rounding up --increment 6m
+  gotrack timesheet --markdown
+  gotrack timesheet --output csv
+`,
+		Args: cobra.NoArgs,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringVar(&c.week, "week", "", "ISO week to show, e.g. 2026-W41, this or last (default this week)")
+	cmd.Flags().StringVar(&c.by, "by", string(analytics.GroupTask), "Rows of the timesheet: task or project")
+	cmd.Flags().StringVar(&c.task, "task", "", "Only include sessions of a task")
+	cmd.Flags().StringVar(&c.project, "project", "", "Only include sessions of a project")
+	cmd.Flags().StringArrayVar(&c.tags, "tag", nil, "Only include sessions with a tag, can be repeated")
+	cmd.Flags().StringVar(&c.rounding, "rounding", c.rounding, "Rounding: none, nearest, up or down, overrides timesheet.rounding")
+	cmd.Flags().Var(&c.increment, "increment", "Step to round to: 6m, 15m or 30m, overrides timesheet.increment")
+	cmd.Flags().StringVar(&c.roundPer, "round-per", c.roundPer, "Round every session or the time per day: session or day, overrides timesheet.round_per")
+	cmd.Flags().Var(&c.minimum, "minimum", "Least time billed for an entry with tracked time, overrides timesheet.minimum")
+	cmd.Flags().BoolVar(&c.markdown, "markdown", false, "Write the timesheet as a Markdown table")
+
+	return cmd
+}
+
+func (c *timesheetCmd) run(cmd *cobra.Command, args []string) error {
+	sm := c.sessionManager
+	if sm == nil {
+		sm = GetSessionManager()
+		if sm == nil {
+			fmt.Println("No session manager available. Please ensure GoTrack is properly initialized.")
+			return fmt.Errorf("session manager not initialized")
+		}
+	}
+
+	by := analytics.GroupBy(c.by)
+	if by != analytics.GroupTask && by != analytics.GroupProject {
+		return fmt.Errorf("invalid --by %q, expected task or project", c.by)
+	}
+	rounding, err := c.roundingRules(cmd)
+	if err != nil {
+		return err
+	}
+	if c.markdown && machineOutput() {
+		return fmt.Errorf("--markdown cannot be combined with --output %s", outputFlag)
+	}
+
+	days, now := reportDays(), time.Now()
+	monday, err := days.ParseWeek(c.week, now)
+	if err != nil {
+		return err
+	}
+
+	ssns, err := sm.GetAllSessions()
+	if err != nil {
+		return fmt.Errorf("failed to get sessions: %v", err)
+	}
+
+	query := analytics.Query{Task: c.task, Project: c.project, Tags: c.tags, Days: days, Now: now}
+	sheet := analytics.BuildTimesheet(ssns, query, monday, by, rounding)
+	if machineOutput() {
+		return writeOutput(newTimesheetOutput(sheet, rounding, days))
+	}
+	if c.markdown {
+		return writeTimesheetMarkdown(os.Stdout, sheet, rounding)
+	}
+	return writeTimesheet(os.Stdout, sheet, rounding)
+}
+
+// roundingRules returns the rounding of the config with the flags given
+// applied over it
+func (c *timesheetCmd) roundingRules(cmd *cobra.Command) (analytics.Rounding, error) {
+	t := appConfig.Timesheet
+	if cmd.Flags().Changed("rounding") {
+		t.Rounding = c.rounding
+	}
+	if cmd.Flags().Changed("increment") {
+		t.Increment = c.increment
+	}
+	if cmd.Flags().Changed("round-per") {
+		t.RoundPer = c.roundPer
+	}
+	if cmd.Flags().Changed("minimum") {
+		t.Minimum = c.minimum
+	}
+
+	switch {
+	case !slices.Contains(cfg.TimesheetRoundings, t.Rounding):
+		return analytics.Rounding{}, fmt.Errorf("invalid rounding %q, expected one of %s", t.Rounding, strings.Join(cfg.TimesheetRoundings, ", "))
+	case !slices.Contains(cfg.TimesheetIncrements, t.Increment):
+		return analytics.Rounding{}, fmt.Errorf("invalid increment %s, expected 6m, 15m or 30m", t.Increment)
+	case !slices.Contains(cfg.TimesheetRoundPer, t.RoundPer):
+		return analytics.Rounding{}, fmt.Errorf("invalid --round-per %q, expected session or day", t.RoundPer)
+	case t.Minimum < 0:
+		return analytics.Rounding{}, fmt.Errorf("the minimum cannot be negative, got %s", t.Minimum)
+	}
+	return analytics.Rounding{
+		Mode:      t.Rounding,
+		Increment: time.Duration(t.Increment),
+		Per:       t.RoundPer,
+		Minimum:   time.Duration(t.Minimum),
+	}, nil
+}
+
+// timesheetCells returns the header and the rows of a timesheet, ending with
+// the totals
+func timesheetCells(t *analytics.Timesheet) (header []string, rows [][]string) {
+	header = []string{strings.ToUpper(string(t.By))}
+	for _, day := range t.Days {
+		header = append(header, day.Format("Mon 2"))
+	}
+	header = append(header, "TOTAL")
+
+	row := func(key string, days [7]time.Duration, total time.Duration) []string {
+		cells := []string{key}
+		for _, d := range days {
+			cells = append(cells, formatClock(d))
+		}
+		return append(cells, formatClock(total))
+	}
+	for _, r := range t.Rows {
+		rows = append(rows, row(r.Key, r.Days, r.Total))
+	}
+	rows = append(rows, row("TOTAL", t.Totals, t.Total))
+	return header, rows
+}
+
+func writeTimesheet(out io.Writer, t *analytics.Timesheet, r analytics.Rounding) error {
+	fmt.Fprintln(out, timesheetTitle(t))
+	if len(t.Rows) == 0 {
+		fmt.Fprintln(out, "No sessions found")
+		return nil
+	}
+
+	fmt.Fprintln(out)
+	header, rows := timesheetCells(t)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, cells := range append([][]string{header}, rows...) {
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, roundingSummary(t, r))
+	return nil
+}
+
+func writeTimesheetMarkdown(out io.Writer, t *analytics.Timesheet, r analytics.Rounding) error {
+	fmt.Fprintf(out, "## %s\n\n", timesheetTitle(t))
+	if len(t.Rows) == 0 {
+		fmt.Fprintln(out, "No sessions found")
+		return nil
+	}
+
+	header, rows := timesheetCells(t)
+	header[0] = strings.ToUpper(header[0][:1]) + strings.ToLower(header[0][1:])
+	header[len(header)-1] = "Total"
+	fmt.Fprintln(out, markdownRow(header))
+	align := []string{"---"}
+	for range header[1:] {
+		align = append(align, "---:")
+	}
+	fmt.Fprintln(out, markdownRow(align))
+	for i, cells := range rows {
+		if i == len(rows)-1 {
+			for j := range cells {
+				cells[j] = "**" + cells[j] + "**"
+			}
+			cells[0] = "**Total**"
+		}
+		fmt.Fprintln(out, markdownRow(cells))
+	}
+	fmt.Fprintf(out, "\n%s\n", roundingSummary(t, r))
+	return nil
+}
+
+// markdownRow joins the cells of a Markdown table row, escaping the pipes
+// within them
+func markdownRow(cells []string) string {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = strings.ReplaceAll(cell, "|", `\|`)
+	}
+	return "| " + strings.Join(escaped, " | ") + " |"
+}
+
+func timesheetTitle(t *analytics.Timesheet) string {
+	year, week := t.Week()
+	return fmt.Sprintf("Timesheet for week %d of %d (%s - %s)",
+		week, year, t.Days[0].Format("Jan 2"), t.Days[6].Format("Jan 2"))
+}
+
+// roundingSummary describes the rounding applied and the time tracked
+// before it
+func roundingSummary(t *analytics.Timesheet, r analytics.Rounding) string {
+	var rules []string
+	if r.Mode != analytics.RoundNone {
+		to := "to the nearest"
+		if r.Mode != analytics.RoundNearest {
+			to = r.Mode + " to"
+		}
+		rules = append(rules, fmt.Sprintf("rounded %s %s per %s", to, cfg.Duration(r.Increment), r.Per))
+	}
+	if r.Minimum > 0 {
+		rules = append(rules, fmt.Sprintf("at least %s per entry", cfg.Duration(r.Minimum)))
+	}
+	if len(rules) == 0 {
+		return "Not rounded"
+	}
+	summary := strings.Join(rules, ", ")
+	return fmt.Sprintf("%s%s, %s tracked", strings.ToUpper(summary[:1]), summary[1:], formatClock(t.Tracked))
+}
+
+// formatClock formats a duration in hours and minutes, e.g. 1:05, with a
+// dash for zero
+func formatClock(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	minutes := int(d.Round(time.Minute).Minutes())
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
@@ -1,35 +1,293 @@
 package config
 
-import "time"
+import (
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Config holds the application configuration
 type Config struct {
 	Pomodoro PomodoroConfig `yaml:"pomodoro"`
+	Hooks    HooksConfig    `yaml:"hooks"`
+	Reports  ReportsConfig  `yaml:"reports"`
+	// Budgets maps a budget name, e.g. "client-a", to the time available
+	Budgets   map[string]BudgetConfig `yaml:"budgets,omitempty"`
+	Timesheet TimesheetConfig         `yaml:"timesheet"`
+	Standup   StandupConfig           `yaml:"standup"`
+	Session   SessionConfig           `yaml:"session"`
+}
+
+// BudgetPeriods are the periods a budget can be set for
+var BudgetPeriods = []string{"day", "week", "month", "year"}
+
+// BudgetConfig limits the time spent on a task, a tag or a project per day,
+// week, month or year
+type BudgetConfig struct {
+	// Task, Tag and Project select the sessions counted against the budget,
+	// exactly one of them is set
+	Task    string `yaml:"task,omitempty"`
+	Tag     string `yaml:"tag,omitempty"`
+	Project string `yaml:"project,omitempty"`
+	// Limit is the time available in each period
+	Limit Duration `yaml:"limit"`
+	// Period is one of "day", "week", "month" or "year"
+	Period string `yaml:"period"`
+}
+
+// TimesheetRoundings are the ways timesheet durations can be rounded
+var TimesheetRoundings = []string{"none", "nearest", "up", "down"}
+
+// TimesheetIncrements are the steps timesheet durations can be rounded to
+var TimesheetIncrements = []Duration{Duration(6 * time.Minute), Duration(15 * time.Minute), Duration(30 * time.Minute)}
+
+// TimesheetRoundPer are the entries rounding is applied to
+var TimesheetRoundPer = []string{"session", "day"}
+
+// TimesheetConfig holds the rounding rules of timesheets
+type TimesheetConfig struct {
+	// Rounding is one of "none", "nearest", "up" or "down"
+	Rounding string `yaml:"rounding"`
+	// Increment is the step durations are rounded to: 6m, 15m or 30m
+	Increment Duration `yaml:"increment"`
+	// RoundPer is "session" to round every session, or "day" to round the
+	// time spent on a task or project per day
+	RoundPer string `yaml:"round_per"`
+	// Minimum is the least time billed for an entry with tracked time,
+	// zero disables it
+	Minimum Duration `yaml:"minimum"`
+}
+
+// StandupConfig holds the days standups look back over
+type StandupConfig struct {
+	// WorkingDays are the days of the week worked on, e.g. "monday"
+	WorkingDays []string `yaml:"working_days"`
+	// Holidays are dates not worked on, e.g. "2026-12-25"
+	Holidays []string `yaml:"holidays,omitempty"`
+}
+
+// SessionConfig holds the defaults for new sessions, usually set by the
+// .gotrack.yaml file of a project
+type SessionConfig struct {
+	// Project is the project new sessions belong to
+	Project string `yaml:"project,omitempty"`
+	// Tags are added to new sessions
+	Tags []string `yaml:"tags,omitempty"`
+	// Task is the task template used when no task is given. It may contain
+	// the placeholders {project}, {dir}, {branch} and {date}.
+	Task string `yaml:"task,omitempty"`
 }
 
 // PomodoroConfig holds the configuration for the Pomodoro timer
 type PomodoroConfig struct {
 	// WorkDuration is the duration of a work session
-	WorkDuration time.Duration `yaml:"work_duration"`
+	WorkDuration Duration `yaml:"work_duration"`
 	// BreakDuration is the duration of a short break
-	BreakDuration time.Duration `yaml:"break_duration"`
+	BreakDuration Duration `yaml:"break_duration"`
 	// LongBreak is the duration of a long break
-	LongBreak time.Duration `yaml:"long_break"`
+	LongBreak Duration `yaml:"long_break"`
 	// LongBreakInterval is the number of work sessions before a long break
 	LongBreakInterval int `yaml:"long_break_interval"`
 	// AutoStartBreak whether to auto-start the next break
 	AutoStartBreak bool `yaml:"auto_start_break"`
+	// ExtendOnSuspend whether time spent suspended is added back to the
+	// running phase instead of counting towards it
+	ExtendOnSuspend bool `yaml:"extend_on_suspend"`
+	// Notifications configures the notifications sent at phase boundaries
+	Notifications NotificationsConfig `yaml:"notifications"`
+	// Mode selects the phase sequence: "classic", "flowtime", a work/break
+	// ratio in minutes such as "52/17", or the name of a custom sequence
+	Mode string `yaml:"mode"`
+	// Flowtime configures the flowtime mode
+	Flowtime FlowtimeConfig `yaml:"flowtime"`
+	// Sequences defines custom phase sequences by name
+	Sequences map[string][]PhaseConfig `yaml:"sequences,omitempty"`
+	// DailyGoal is the number of pomodoros to complete each day, zero
+	// disables the goal
+	DailyGoal int `yaml:"daily_pomodoro_goal"`
+}
+
+// Built-in timer modes, implemented by the pomodoro package
+const (
+	ModeClassic  = "classic"
+	ModeFlowtime = "flowtime"
+)
+
+// ParseRatio parses a "work/break" timer mode given in minutes, e.g. "52/17"
+func ParseRatio(mode string) (work, brk time.Duration, ok bool) {
+	w, b, found := strings.Cut(mode, "/")
+	if !found {
+		return 0, 0, false
+	}
+	wm, err := strconv.Atoi(w)
+	if err != nil || wm <= 0 {
+		return 0, 0, false
+	}
+	bm, err := strconv.Atoi(b)
+	if err != nil || bm <= 0 {
+		return 0, 0, false
+	}
+	return time.Duration(wm) * time.Minute, time.Duration(bm) * time.Minute, true
+}
+
+// FlowtimeConfig holds the configuration for the flowtime mode, where work
+// is open-ended and the break is proportional to the time worked
+type FlowtimeConfig struct {
+	// BreakRatio is the amount of work per unit of break, e.g. 5 gives a
+	// 5 minute break after 25 minutes of work
+	BreakRatio float64 `yaml:"break_ratio"`
+	// MinBreak is the shortest break
+	MinBreak Duration `yaml:"min_break"`
+	// MaxBreak is the longest break, zero means no limit
+	MaxBreak Duration `yaml:"max_break"`
+}
+
+// PhaseConfig describes one phase of a custom sequence
+type PhaseConfig struct {
+	Name string `yaml:"name"`
+	// Kind is either "work" or "break"
+	Kind     string   `yaml:"kind"`
+	Duration Duration `yaml:"duration"`
+}
+
+// Notification backends, implemented by the notify package
+const (
+	BackendAuto   = "auto"
+	BackendDBus   = "dbus"
+	BackendBell   = "bell"
+	BackendOSC9   = "osc9"
+	BackendOSC777 = "osc777"
+	BackendNone   = "none"
+)
+
+// NotificationBackends lists every notification backend
+var NotificationBackends = []string{BackendAuto, BackendDBus, BackendBell, BackendOSC9, BackendOSC777, BackendNone}
+
+// NotificationsConfig holds the configuration for desktop notifications
+type NotificationsConfig struct {
+	// Enabled whether notifications are sent at all
+	Enabled bool `yaml:"enabled"`
+	// Backend is one of "auto", "dbus", "bell", "osc9", "osc777" or "none"
+	Backend string `yaml:"backend"`
+}
+
+// HooksConfig holds the shell commands run on gotrack events
+type HooksConfig struct {
+	// Timeout is the maximum time a single hook command may run
+	Timeout Duration `yaml:"timeout"`
+	// Events maps an event name, e.g. "pomodoro.work_started", to the
+	// shell commands run when it happens
+	Events map[string][]string `yaml:"events"`
+}
+
+// ReportsConfig holds the configuration for statistics and reports
+type ReportsConfig struct {
+	// Timezone is the IANA name of the timezone days are counted in, e.g.
+	// "Europe/Kyiv". Empty means the local timezone.
+	Timezone string `yaml:"timezone"`
+	// DayStartHour is the hour, between 0 and 23, at which a day begins.
+	// Night owls can set it to e.g. 4 so that work after midnight counts
+	// towards the previous day.
+	DayStartHour int `yaml:"day_start_hour"`
+	// Score configures the productivity score
+	Score ScoreConfig `yaml:"score"`
+}
+
+// ScoreConfig holds the targets and weights of the productivity score. Each
+// component scores the share of its target reached within the window.
+type ScoreConfig struct {
+	// WindowDays is the number of days, up to today, the score looks at
+	WindowDays int `yaml:"window_days"`
+	// VolumeTarget is the time tracked within the window that gives the
+	// full volume score
+	VolumeTarget Duration `yaml:"volume_target"`
+	// ActiveDaysTarget is the number of days with tracked time that gives
+	// the full consistency score
+	ActiveDaysTarget int `yaml:"active_days_target"`
+	// StreakTarget is the length of the longest streak of tracked days that
+	// gives the full streak score
+	StreakTarget int `yaml:"streak_target"`
+	// Weights sets how much each component counts towards the score
+	Weights ScoreWeights `yaml:"weights"`
+}
+
+// ScoreWeights are the relative weights of the components of the score,
+// they do not have to add up to 1
+type ScoreWeights struct {
+	Volume      float64 `yaml:"volume"`
+	Consistency float64 `yaml:"consistency"`
+	Streak      float64 `yaml:"streak"`
+}
+
+// Location returns the timezone days are counted in
+func (r *ReportsConfig) Location() (*time.Location, error) {
+	if r.Timezone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(r.Timezone)
+}
+
+// weekdays maps the lowercase names of the days of the week to them
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+// Weekdays returns the working days of the week
+func (s *StandupConfig) Weekdays() []time.Weekday {
+	var days []time.Weekday
+	for _, day := range s.WorkingDays {
+		if wd, ok := weekdays[strings.ToLower(day)]; ok {
+			days = append(days, wd)
+		}
+	}
+	return days
 }
 
 // Default returns the default application configuration
 func Default() *Config {
 	return &Config{
 		Pomodoro: PomodoroConfig{
-			WorkDuration:     25 * time.Minute,
-			BreakDuration:    5 * time.Minute,
-			LongBreak:        15 * time.Minute,
+			WorkDuration:      Duration(25 * time.Minute),
+			BreakDuration:     Duration(5 * time.Minute),
+			LongBreak:         Duration(15 * time.Minute),
 			LongBreakInterval: 4,
-			AutoStartBreak:   true,
+			AutoStartBreak:    true,
+			Notifications: NotificationsConfig{
+				Enabled: true,
+				Backend: BackendAuto,
+			},
+			Mode: ModeClassic,
+			Flowtime: FlowtimeConfig{
+				BreakRatio: 5,
+				MinBreak:   Duration(time.Minute),
+				MaxBreak:   Duration(30 * time.Minute),
+			},
+			DailyGoal: 8,
+		},
+		Hooks: HooksConfig{
+			Timeout: Duration(10 * time.Second),
+		},
+		Timesheet: TimesheetConfig{
+			Rounding:  "none",
+			Increment: Duration(15 * time.Minute),
+			RoundPer:  "day",
+		},
+		Standup: StandupConfig{
+			WorkingDays: []string{"monday", "tuesday", "wednesday", "thursday", "friday"},
+		},
+		Reports: ReportsConfig{
+			Score: ScoreConfig{
+				WindowDays:       30,
+				VolumeTarget:     Duration(100 * time.Hour),
+				ActiveDaysTarget: 20,
+				StreakTarget:     10,
+				Weights: ScoreWeights{
+					Volume:      0.4,
+					Consistency: 0.4,
+					Streak:      0.2,
+				},
+			},
 		},
 	}
 }
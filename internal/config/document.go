@@ -0,0 +1,225 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultIndent is the indentation yaml.Marshal uses, and thus Config.Save
+const defaultIndent = 4
+
+// Document is a config file kept as a YAML document, so that single settings
+// can be changed without losing the comments and layout of the file
+type Document struct {
+	path   string
+	root   yaml.Node
+	indent int
+}
+
+// OpenDocument reads the config file at path. A missing file yields an
+// empty document that is created on Save. An empty path stands for the
+// default config path.
+func OpenDocument(path string) (*Document, error) {
+	if path == "" {
+		var err error
+		if path, err = DefaultPath(); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+	d := &Document{path: path, indent: detectIndent(data)}
+	if err := yaml.Unmarshal(data, &d.root); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %v", err)
+	}
+
+	if d.root.Kind == 0 {
+		d.root = yaml.Node{
+			Kind:    yaml.DocumentNode,
+			Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}},
+		}
+	}
+	if d.root.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("failed to parse config file: the top level must be a mapping")
+	}
+	return d, nil
+}
+
+// Path returns the path of the config file
+func (d *Document) Path() string {
+	return d.path
+}
+
+// Config decodes the document on top of the defaults and validates it
+func (d *Document) Config() (*Config, error) {
+	cfg := Default()
+	if err := d.root.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			verr.locate(&d.root)
+		}
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Set stores value under the dotted key. The value must suit the type of
+// the setting and must not make any setting invalid. Settings that were
+// already invalid do not prevent the change.
+func (d *Document) Set(key, value string) error {
+	segments, t, err := resolveKey(reflect.TypeOf(Config{}), key)
+	if err != nil {
+		return err
+	}
+	node, err := parseValue(t, key, value)
+	if err != nil {
+		return err
+	}
+
+	invalid, err := d.invalidFields()
+	if err != nil {
+		return err
+	}
+	backup := deepCopy(&d.root)
+
+	parent := d.root.Content[0]
+	for _, segment := range segments[:len(segments)-1] {
+		keyNode, next := mappingEntry(parent, segment)
+		switch {
+		case next == nil:
+			next = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			parent.Content = append(parent.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: segment}, next)
+		case next.Kind != yaml.MappingNode:
+			return fmt.Errorf("line %d: %s is not a section", keyNode.Line, segment)
+		}
+		parent = next
+	}
+
+	last := segments[len(segments)-1]
+	if _, old := mappingEntry(parent, last); old != nil {
+		// Keep the comments attached to the old value
+		node.HeadComment, node.LineComment, node.FootComment = old.HeadComment, old.LineComment, old.FootComment
+		*old = *node
+	} else {
+		parent.Content = append(parent.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: last}, node)
+	}
+
+	var introduced []FieldError
+	if _, err := d.Config(); err != nil {
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			d.root = *backup
+			return err
+		}
+		for _, fe := range verr.Errors {
+			if !invalid[fe.Field] {
+				introduced = append(introduced, fe)
+			}
+		}
+	}
+	if len(introduced) > 0 {
+		d.root = *backup
+		return &ValidationError{Errors: introduced}
+	}
+	return nil
+}
+
+// invalidFields returns the fields of the document that fail validation
+func (d *Document) invalidFields() (map[string]bool, error) {
+	invalid := make(map[string]bool)
+	if _, err := d.Config(); err != nil {
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			return nil, err
+		}
+		for _, fe := range verr.Errors {
+			invalid[fe.Field] = true
+		}
+	}
+	return invalid, nil
+}
+
+// deepCopy returns a copy of node that shares no nodes with it
+func deepCopy(node *yaml.Node) *yaml.Node {
+	c := *node
+	c.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		c.Content[i] = deepCopy(child)
+	}
+	return &c
+}
+
+// Unset removes the dotted key from the document, so the setting goes back
+// to its default. It reports whether the key was present.
+func (d *Document) Unset(key string) (bool, error) {
+	path, _, err := resolveKey(reflect.TypeOf(Config{}), key)
+	if err != nil {
+		return false, err
+	}
+
+	return removeKey(d.root.Content[0], path), nil
+}
+
+// removeKey removes the entry at path from the mapping node, along with the
+// sections that are left empty by it. It reports whether the entry existed.
+func removeKey(mapping *yaml.Node, path []string) bool {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != path[0] {
+			continue
+		}
+
+		if len(path) > 1 {
+			child := mapping.Content[i+1]
+			if child.Kind != yaml.MappingNode || !removeKey(child, path[1:]) {
+				return false
+			}
+			if len(child.Content) > 0 {
+				return true
+			}
+		}
+		mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+		return true
+	}
+	return false
+}
+
+// Save writes the document back to its file
+func (d *Document) Save() error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(d.indent)
+	if err := enc.Encode(&d.root); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	return writeFile(d.path, buf.Bytes())
+}
+
+// detectIndent returns the indentation used by the YAML file, so that saving
+// it keeps its layout. Files without nested keys get the indentation written
+// by Config.Save.
+func detectIndent(data []byte) int {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if indent := len(line) - len(trimmed); indent > 0 && trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			return max(indent, 2)
+		}
+	}
+	return defaultIndent
+}
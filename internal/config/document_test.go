@@ -0,0 +1,202 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AndriyBarskyi/gotrack/internal/config"
+)
+
+const commentedConfig = `# my settings
+pomodoro:
+  # focus length
+  work_duration: 25m # classic
+  long_break_interval: 4
+`
+
+func openTestDocument(t *testing.T, content string) (*config.Document, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if content != "" {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	doc, err := config.OpenDocument(path)
+	require.NoError(t, err)
+	return doc, path
+}
+
+func TestDocument_SetKeepsComments(t *testing.T) {
+	doc, path := openTestDocument(t, commentedConfig)
+
+	require.NoError(t, doc.Set("pomodoro.work_duration", "50"))
+	require.NoError(t, doc.Set("hooks.timeout", "3s"))
+	require.NoError(t, doc.Save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `# my settings
+pomodoro:
+  # focus length
+  work_duration: 50m # classic
+  long_break_interval: 4
+hooks:
+  timeout: 3s
+`, string(data))
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, config.Duration(50*time.Minute), cfg.Pomodoro.WorkDuration)
+	assert.Equal(t, config.Duration(3*time.Second), cfg.Hooks.Timeout)
+}
+
+func TestDocument_SetChecksValues(t *testing.T) {
+	doc, _ := openTestDocument(t, commentedConfig)
+
+	var unknown *config.ErrUnknownKey
+	assert.True(t, errors.As(doc.Set("pomodoro.nap_duration", "5m"), &unknown))
+	assert.True(t, errors.As(doc.Set("pomodoro.work_duration.minutes", "5"), &unknown))
+	assert.ErrorContains(t, doc.Set("pomodoro.auto_start_break", "maybe"), "expected true or false")
+	assert.ErrorContains(t, doc.Set("pomodoro.long_break_interval", "four"), "expected a whole number")
+	assert.ErrorContains(t, doc.Set("pomodoro.sequences", "x"), "cannot be set")
+
+	var verr *config.ValidationError
+	require.True(t, errors.As(doc.Set("pomodoro.long_break_interval", "0"), &verr))
+	assert.Equal(t, 5, verr.Errors[0].Line)
+
+	cfg, err := doc.Config()
+	require.NoError(t, err)
+	assert.Equal(t, 4, cfg.Pomodoro.LongBreakInterval, "Rejected values should not be kept")
+}
+
+func TestDocument_SetWithOtherInvalidSettings(t *testing.T) {
+	doc, _ := openTestDocument(t, "pomodoro:\n  long_break_interval: 0\n")
+
+	require.NoError(t, doc.Set("pomodoro.work_duration", "50m"))
+	require.NoError(t, doc.Set("pomodoro.long_break_interval", "2"))
+
+	cfg, err := doc.Config()
+	require.NoError(t, err)
+	assert.Equal(t, 2, cfg.Pomodoro.LongBreakInterval)
+}
+
+func TestDocument_Unset(t *testing.T) {
+	doc, path := openTestDocument(t, commentedConfig+"hooks:\n  timeout: 3s\n")
+
+	removed, err := doc.Unset("hooks.timeout")
+	require.NoError(t, err)
+	assert.True(t, removed)
+
+	removed, err = doc.Unset("pomodoro.mode")
+	require.NoError(t, err)
+	assert.False(t, removed)
+
+	_, err = doc.Unset("hooks.nothing")
+	assert.Error(t, err)
+
+	require.NoError(t, doc.Save())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, commentedConfig, string(data), "Emptied sections should be removed")
+}
+
+func TestDocument_MissingFile(t *testing.T) {
+	doc, path := openTestDocument(t, "")
+
+	require.NoError(t, doc.Set("pomodoro.mode", "flowtime"))
+	require.NoError(t, doc.Save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "pomodoro:\n    mode: flowtime\n", string(data))
+}
+
+func TestConfig_GetAndSettings(t *testing.T) {
+	cfg := config.Default()
+
+	value, err := cfg.Get("pomodoro.work_duration")
+	require.NoError(t, err)
+	assert.Equal(t, "25m", value)
+
+	value, err = cfg.Get("pomodoro.notifications")
+	require.NoError(t, err)
+	assert.Equal(t, "enabled: true\nbackend: auto", value)
+
+	_, err = cfg.Get("pomodoro.unknown")
+	assert.Error(t, err)
+
+	settings, err := cfg.Settings()
+	require.NoError(t, err)
+	assert.Equal(t, config.Setting{Key: "pomodoro.work_duration", Value: "25m"}, settings[0])
+	assert.Contains(t, settings, config.Setting{Key: "pomodoro.flowtime.break_ratio", Value: "5"})
+	assert.Contains(t, settings, config.Setting{Key: "hooks.events", Value: "{}"})
+}
+
+const mapsConfig = `budgets:
+  client-a:
+    project: client-a
+    limit: 10h # per week
+    period: week
+hooks:
+  events:
+    session.started:
+      - notify-send started
+`
+
+func TestDocument_SetMapEntries(t *testing.T) {
+	doc, path := openTestDocument(t, mapsConfig)
+
+	require.NoError(t, doc.Set("budgets.client-a.limit", "20h"))
+
+	err := doc.Set("budgets.client-a.owner", "me")
+	var unknown *config.ErrUnknownKey
+	assert.ErrorAs(t, err, &unknown)
+
+	err = doc.Set("hooks.events.session.started", "notify-send")
+	assert.ErrorContains(t, err, "hooks.events.session.started cannot be set from the command line")
+	err = doc.Set("pomodoro.sequences.writing", "draft")
+	assert.ErrorContains(t, err, "pomodoro.sequences.writing cannot be set from the command line")
+
+	removed, err := doc.Unset("hooks.events.session.started")
+	require.NoError(t, err)
+	assert.True(t, removed)
+
+	require.NoError(t, doc.Save())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "budgets:\n  client-a:\n    project: client-a\n    limit: 20h # per week\n    period: week\n", string(data))
+}
+
+func TestConfig_GetMapEntries(t *testing.T) {
+	cfg := config.Default()
+	cfg.Budgets = map[string]config.BudgetConfig{
+		"client-a": {Project: "client-a", Limit: config.Duration(10 * time.Hour), Period: "week"},
+	}
+	cfg.Hooks.Events = map[string][]string{"session.started": {"notify-send started"}}
+
+	value, err := cfg.Get("budgets.client-a.limit")
+	require.NoError(t, err)
+	assert.Equal(t, "10h", value)
+
+	value, err = cfg.Get("hooks.events.session.started")
+	require.NoError(t, err)
+	assert.Equal(t, "[notify-send started]", value)
+
+	_, err = cfg.Get("budgets.client-b.limit")
+	assert.Error(t, err)
+
+	settings, err := cfg.Settings()
+	require.NoError(t, err)
+	assert.Contains(t, settings, config.Setting{Key: "budgets.client-a.limit", Value: "10h"})
+	assert.Contains(t, settings, config.Setting{Key: "hooks.events.session.started", Value: "[notify-send started]"})
+
+	applied, err := cfg.ApplyEnv([]string{"GOTRACK_BUDGETS_CLIENT-A_LIMIT=20h"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"budgets.client-a.limit"}, applied)
+	assert.Equal(t, config.Duration(20*time.Hour), cfg.Budgets["client-a"].Limit)
+}
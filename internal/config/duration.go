@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// legacyNanoseconds is the smallest plain number read as nanoseconds rather
+// than minutes. Config files written by older versions stored durations as
+// raw nanosecond counts, which are always at least a second.
+const legacyNanoseconds = int64(time.Second)
+
+// Duration is a time.Duration that is written to YAML in a readable form
+// such as "25m" or "1h30m". It reads the same form, plain numbers of
+// minutes, and the nanosecond counts written by older versions.
+type Duration time.Duration
+
+// String returns the duration without trailing zero units, e.g. "1h30m"
+func (d Duration) String() string {
+	s := time.Duration(d).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// ParseDuration parses a duration such as "25m" or "1h30m". A plain number
+// is a number of minutes.
+func ParseDuration(s string) (Duration, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return minutesOrNanoseconds(n), nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q, expected e.g. \"25m\", \"1h30m\" or a number of minutes", s)
+	}
+	return Duration(d), nil
+}
+
+func minutesOrNanoseconds(n int64) Duration {
+	if n >= legacyNanoseconds || n <= -legacyNanoseconds {
+		return Duration(n)
+	}
+	return Duration(time.Duration(n) * time.Minute)
+}
+
+// Set implements pflag.Value, so durations can be given as flags
+func (d *Duration) Set(s string) error {
+	parsed, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Type implements pflag.Value
+func (d *Duration) Type() string {
+	return "duration"
+}
+
+// MarshalYAML implements yaml.Marshaler
+func (d Duration) MarshalYAML() (any, error) {
+	return d.String(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: expected a duration", node.Line)
+	}
+
+	parsed, err := ParseDuration(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %v", node.Line, err)
+	}
+	*d = parsed
+	return nil
+}
+
+// upgradeLegacyDurations rewrites in the readable form the durations of the
+// document, holding a value of type t, that an older version wrote as
+// nanoseconds, leaving the rest of the document as it is. It reports whether
+// anything was rewritten.
+func upgradeLegacyDurations(node *yaml.Node, t reflect.Type) bool {
+	upgraded := false
+	switch {
+	case node.Kind == yaml.DocumentNode:
+		for _, child := range node.Content {
+			upgraded = upgradeLegacyDurations(child, t) || upgraded
+		}
+	case t == durationType:
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!int" {
+			return false
+		}
+		n, err := strconv.ParseInt(node.Value, 10, 64)
+		if err != nil || (n < legacyNanoseconds && n > -legacyNanoseconds) {
+			return false
+		}
+		node.Tag, node.Value = "!!str", Duration(n).String()
+		return true
+	case node.Kind == yaml.MappingNode && isMap(t):
+		for i := 1; i < len(node.Content); i += 2 {
+			upgraded = upgradeLegacyDurations(node.Content[i], t.Elem()) || upgraded
+		}
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Struct:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if index := fieldIndex(t, node.Content[i].Value); index != nil {
+				upgraded = upgradeLegacyDurations(node.Content[i+1], t.FieldByIndex(index).Type) || upgraded
+			}
+		}
+	case node.Kind == yaml.SequenceNode && t.Kind() == reflect.Slice:
+		for _, child := range node.Content {
+			upgraded = upgradeLegacyDurations(child, t.Elem()) || upgraded
+		}
+	}
+	return upgraded
+}
@@ -0,0 +1,131 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/AndriyBarskyi/gotrack/internal/config"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{input: "25m", expected: 25 * time.Minute},
+		{input: "1h30m", expected: 90 * time.Minute},
+		{input: "45s", expected: 45 * time.Second},
+		{input: "25", expected: 25 * time.Minute},
+		{input: "0", expected: 0},
+		{input: "1500000000000", expected: 25 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			d, err := config.ParseDuration(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, time.Duration(d))
+		})
+	}
+
+	_, err := config.ParseDuration("soon")
+	assert.Error(t, err)
+}
+
+func TestDuration_String(t *testing.T) {
+	assert.Equal(t, "25m", config.Duration(25*time.Minute).String())
+	assert.Equal(t, "1h", config.Duration(time.Hour).String())
+	assert.Equal(t, "1h30m", config.Duration(90*time.Minute).String())
+	assert.Equal(t, "1m30s", config.Duration(90*time.Second).String())
+	assert.Equal(t, "0s", config.Duration(0).String())
+}
+
+func TestDuration_YAML(t *testing.T) {
+	data, err := yaml.Marshal(config.Default().Pomodoro)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "work_duration: 25m\n")
+
+	var pc config.PomodoroConfig
+	require.NoError(t, yaml.Unmarshal([]byte("work_duration: 50\nbreak_duration: 1h5m\n"), &pc))
+	assert.Equal(t, config.Duration(50*time.Minute), pc.WorkDuration)
+	assert.Equal(t, config.Duration(65*time.Minute), pc.BreakDuration)
+
+	err = yaml.Unmarshal([]byte("work_duration: later\n"), &pc)
+	assert.ErrorContains(t, err, "line 1")
+}
+
+func TestLoad_RewritesLegacyDurations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	legacy := "# My timer\npomodoro:\n  long_break: 15m # after lunch\n  work_duration: 3000000000000\n"
+	require.NoError(t, os.WriteFile(path, []byte(legacy), 0644))
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, config.Duration(50*time.Minute), cfg.Pomodoro.WorkDuration)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# My timer\npomodoro:\n  long_break: 15m # after lunch\n  work_duration: 50m\n", string(data),
+		"Only the legacy durations should change, comments and key order are kept")
+
+	// A readable config is left untouched
+	readable := "pomodoro:\n  work_duration: 50m\n"
+	require.NoError(t, os.WriteFile(path, []byte(readable), 0644))
+	_, err = config.Load(path)
+	require.NoError(t, err)
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, readable, string(data))
+}
+
+func TestLoad_RewritesOnlyLegacyDurations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	legacy := `pomodoro:
+  daily_pomodoro_goal: 1000000000
+  sequences:
+    writing:
+      - kind: work
+        duration: 3000000000000
+budgets:
+  1000000000:
+    project: client-a
+    limit: 36000000000000
+    period: week
+`
+	require.NoError(t, os.WriteFile(path, []byte(legacy), 0644))
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1000000000, cfg.Pomodoro.DailyGoal)
+	assert.Equal(t, config.Duration(10*time.Hour), cfg.Budgets["1000000000"].Limit)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `pomodoro:
+  daily_pomodoro_goal: 1000000000
+  sequences:
+    writing:
+      - kind: work
+        duration: 50m
+budgets:
+  1000000000:
+    project: client-a
+    limit: 10h
+    period: week
+`, string(data), "Numbers that are not durations and map keys are kept")
+}
+
+func TestLoad_EmptyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, nil, 0644))
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, config.Default(), cfg)
+}
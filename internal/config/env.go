@@ -0,0 +1,67 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// envPrefix starts the environment variables that override settings
+const envPrefix = "GOTRACK_"
+
+// HookEnvPrefix starts the environment variables describing the event a hook
+// runs for. They are never read as settings, so that gotrack can be called
+// from a hook.
+const HookEnvPrefix = envPrefix + "HOOK_"
+
+// EnvName returns the environment variable overriding the setting with the
+// dotted key, e.g. GOTRACK_POMODORO_WORK_DURATION for pomodoro.work_duration
+func EnvName(key string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// ApplyEnv overrides settings with the GOTRACK_* variables found in environ,
+// except the GOTRACK_HOOK_* ones,
+// given as "KEY=value" pairs like os.Environ returns. Only settings that can
+// be set from the command line can be overridden. It returns the keys that
+// were overridden; values that do not suit their setting are reported as a
+// *ValidationError.
+func (c *Config) ApplyEnv(environ []string) ([]string, error) {
+	values := make(map[string]string)
+	for _, kv := range environ {
+		if name, value, ok := strings.Cut(kv, "="); ok && strings.HasPrefix(name, envPrefix) &&
+			!strings.HasPrefix(name, HookEnvPrefix) {
+			values[name] = value
+		}
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	var applied []string
+	v := &validator{}
+	err := walkSettings(reflect.ValueOf(c).Elem(), "", func(key string, field reflect.Value) error {
+		value, ok := values[EnvName(key)]
+		if !ok {
+			return nil
+		}
+
+		node, err := parseValue(field.Type(), key, value)
+		if err == nil {
+			err = node.Decode(field.Addr().Interface())
+		}
+		if err != nil {
+			msg := strings.TrimPrefix(err.Error(), key+": ")
+			v.check(false, key, "%s (set by %s)", msg, EnvName(key))
+			return nil
+		}
+		applied = append(applied, key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(v.errs) > 0 {
+		return nil, &ValidationError{Errors: v.errs}
+	}
+	return applied, nil
+}
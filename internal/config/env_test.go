@@ -0,0 +1,44 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AndriyBarskyi/gotrack/internal/config"
+)
+
+func TestEnvName(t *testing.T) {
+	assert.Equal(t, "GOTRACK_POMODORO_WORK_DURATION", config.EnvName("pomodoro.work_duration"))
+	assert.Equal(t, "GOTRACK_POMODORO_NOTIFICATIONS_ENABLED", config.EnvName("pomodoro.notifications.enabled"))
+}
+
+func TestApplyEnv(t *testing.T) {
+	cfg := config.Default()
+	applied, err := cfg.ApplyEnv([]string{
+		"PATH=/usr/bin",
+		"GOTRACK_HOME=/ignored",
+		"GOTRACK_HOOK_POMODORO_WORK_DURATION=ignored",
+		"GOTRACK_POMODORO_WORK_DURATION=50",
+		"GOTRACK_POMODORO_NOTIFICATIONS_ENABLED=false",
+		"GOTRACK_POMODORO_FLOWTIME_BREAK_RATIO=3.5",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"pomodoro.work_duration",
+		"pomodoro.notifications.enabled",
+		"pomodoro.flowtime.break_ratio",
+	}, applied)
+	assert.Equal(t, config.Duration(50*time.Minute), cfg.Pomodoro.WorkDuration)
+	assert.False(t, cfg.Pomodoro.Notifications.Enabled)
+	assert.Equal(t, 3.5, cfg.Pomodoro.Flowtime.BreakRatio)
+
+	_, err = cfg.ApplyEnv([]string{"GOTRACK_POMODORO_LONG_BREAK_INTERVAL=often"})
+	var verr *config.ValidationError
+	require.True(t, errors.As(err, &verr))
+	assert.Equal(t, "pomodoro.long_break_interval", verr.Errors[0].Field)
+	assert.Contains(t, verr.Errors[0].Message, "GOTRACK_POMODORO_LONG_BREAK_INTERVAL")
+}
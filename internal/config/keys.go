@@ -0,0 +1,257 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Setting is a single configuration value addressed by its dotted key
+type Setting struct {
+	// Key is the dotted YAML path, e.g. "pomodoro.work_duration"
+	Key   string
+	Value string
+}
+
+// ErrUnknownKey is returned for keys that are not part of the configuration
+type ErrUnknownKey struct {
+	Key string
+}
+
+// Error implements error
+func (e *ErrUnknownKey) Error() string {
+	return fmt.Sprintf("unknown config key %q", e.Key)
+}
+
+var durationType = reflect.TypeOf(Duration(0))
+
+// Get returns the value of the setting with the dotted key. Keys of nested
+// sections, such as "pomodoro.flowtime", return the whole section as YAML.
+// Entries of maps are addressed by their name, e.g. "budgets.client-a.limit".
+func (c *Config) Get(key string) (string, error) {
+	v, err := lookup(reflect.ValueOf(c).Elem(), key)
+	if err != nil {
+		return "", err
+	}
+	if isLeaf(v.Type()) {
+		return formatValue(v)
+	}
+
+	data, err := yaml.Marshal(v.Interface())
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
+
+// Settings returns every setting of the configuration in file order
+func (c *Config) Settings() ([]Setting, error) {
+	var settings []Setting
+	err := walkSettings(reflect.ValueOf(c).Elem(), "", func(key string, v reflect.Value) error {
+		value, err := formatValue(v)
+		if err != nil {
+			return err
+		}
+		settings = append(settings, Setting{Key: key, Value: value})
+		return nil
+	})
+	return settings, err
+}
+
+func walkSettings(v reflect.Value, prefix string, fn func(string, reflect.Value) error) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := yamlName(t.Field(i))
+		if name == "" {
+			continue
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		if err := walkSetting(v.Field(i), key, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkSetting calls fn for the setting v at key, or for every setting within
+// it when it is a section or a map with entries
+func walkSetting(v reflect.Value, key string, fn func(string, reflect.Value) error) error {
+	switch {
+	case v.Kind() == reflect.Struct && !isLeaf(v.Type()):
+		return walkSettings(v, key, fn)
+	case v.Kind() == reflect.Map && v.Len() > 0:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, k := range keys {
+			// Map entries are not addressable, fn works on a copy that is
+			// stored back
+			entry := reflect.New(v.Type().Elem()).Elem()
+			entry.Set(v.MapIndex(k))
+			if err := walkSetting(entry, key+"."+k.String(), fn); err != nil {
+				return err
+			}
+			v.SetMapIndex(k, entry)
+		}
+		return nil
+	default:
+		return fn(key, v)
+	}
+}
+
+// lookup returns the struct field or map entry addressed by the dotted key
+func lookup(v reflect.Value, key string) (reflect.Value, error) {
+	path, _, err := resolveKey(v.Type(), key)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	for _, segment := range path {
+		if isMap(v.Type()) {
+			v = v.MapIndex(reflect.ValueOf(segment).Convert(v.Type().Key()))
+		} else {
+			v = v.FieldByIndex(fieldIndex(v.Type(), segment))
+		}
+		if !v.IsValid() {
+			return reflect.Value{}, &ErrUnknownKey{Key: key}
+		}
+	}
+	return v, nil
+}
+
+// resolveKey splits the dotted key into the YAML keys leading to the setting
+// in a value of type t and returns the type of the setting. Any name is
+// accepted for map entries, so that new ones can be added, and the name of
+// an entry holding a single setting may contain dots, e.g.
+// "hooks.events.session.started".
+func resolveKey(t reflect.Type, key string) ([]string, reflect.Type, error) {
+	segments := strings.Split(key, ".")
+	var path []string
+	for i := 0; i < len(segments); i++ {
+		segment := segments[i]
+		switch {
+		case isMap(t):
+			if isLeaf(t.Elem()) {
+				segment, i = strings.Join(segments[i:], "."), len(segments)
+			}
+			t = t.Elem()
+		case t.Kind() == reflect.Struct && !isLeaf(t) && fieldIndex(t, segment) != nil:
+			t = t.FieldByIndex(fieldIndex(t, segment)).Type
+		default:
+			return nil, nil, &ErrUnknownKey{Key: key}
+		}
+		path = append(path, segment)
+	}
+	return path, t, nil
+}
+
+// fieldIndex returns the index of the struct field with the YAML key name,
+// or nil when there is none
+func fieldIndex(t reflect.Type, name string) []int {
+	for i := 0; i < t.NumField(); i++ {
+		if yamlName(t.Field(i)) == name {
+			return []int{i}
+		}
+	}
+	return nil
+}
+
+// yamlName returns the YAML key of a struct field, or "" when it has none
+func yamlName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// isLeaf reports whether values of the type are a single setting rather
+// than a section holding further settings
+func isLeaf(t reflect.Type) bool {
+	return t.Kind() != reflect.Struct || t == durationType
+}
+
+// isMap reports whether values of the type are maps with named entries
+func isMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String
+}
+
+// isScalar reports whether values of the type can be set from a string
+func isScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64, reflect.String:
+		return true
+	default:
+		return false
+	}
+}
+
+// formatValue renders a setting the way it is written in the config file
+func formatValue(v reflect.Value) (string, error) {
+	if v.Type() == durationType || isScalar(v.Type()) {
+		return fmt.Sprint(v.Interface()), nil
+	}
+
+	// Lists and maps are shown on a single line in YAML flow style
+	var node yaml.Node
+	if err := node.Encode(v.Interface()); err != nil {
+		return "", err
+	}
+	setFlowStyle(&node)
+	data, err := yaml.Marshal(&node)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
+
+func setFlowStyle(node *yaml.Node) {
+	node.Style |= yaml.FlowStyle
+	for _, child := range node.Content {
+		setFlowStyle(child)
+	}
+}
+
+// parseValue checks that s is a valid value for a setting of type t and
+// returns the YAML node to store it as
+func parseValue(t reflect.Type, key, s string) (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}
+
+	switch {
+	case t == durationType:
+		d, err := ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", key, err)
+		}
+		node.Value = d.String()
+	case t.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: expected true or false, got %q", key, s)
+		}
+		node.Tag, node.Value = "!!bool", strconv.FormatBool(b)
+	case t.Kind() == reflect.Int || t.Kind() == reflect.Int64:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: expected a whole number, got %q", key, s)
+		}
+		node.Tag, node.Value = "!!int", strconv.Itoa(n)
+	case t.Kind() == reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: expected a number, got %q", key, s)
+		}
+		node.Tag, node.Value = "!!float", strconv.FormatFloat(f, 'g', -1, 64)
+	case t.Kind() == reflect.String:
+	default:
+		return nil, fmt.Errorf("%s cannot be set from the command line, edit the config file instead", key)
+	}
+
+	return node, nil
+}
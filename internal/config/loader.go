@@ -1,22 +1,25 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 
 	"gopkg.in/yaml.v3"
 )
 
-// Load loads the configuration from the given path.
+// Load loads the configuration from the given path and validates it.
 // If the file doesn't exist, it creates a default config file and returns it.
+// Invalid values are reported as a *ValidationError that points to the
+// offending lines of the file.
 func Load(path string) (*Config, error) {
 	if path == "" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get home directory: %v", err)
+		var err error
+		if path, err = DefaultPath(); err != nil {
+			return nil, err
 		}
-		path = filepath.Join(homeDir, ".gotrack", "config.yaml")
 	}
 
 	data, err := os.ReadFile(path)
@@ -31,33 +34,70 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %v", err)
 	}
 
-	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
+	// Start from the defaults so that keys missing from older config files
+	// keep their default values
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %v", err)
+	}
+	cfg := Default()
+	if err := doc.Decode(cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %v", err)
 	}
 
-	return &cfg, nil
+	if err := cfg.Validate(); err != nil {
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			verr.locate(&doc)
+		}
+		return nil, fmt.Errorf("invalid config file %s:\n%w", path, err)
+	}
+
+	// Rewrite durations stored as nanoseconds by older versions once, in
+	// the readable form, keeping the comments and layout of the file
+	if upgradeLegacyDurations(&doc, reflect.TypeOf(Config{})) {
+		d := &Document{path: path, root: doc, indent: detectIndent(data)}
+		if err := d.Save(); err != nil {
+			return nil, fmt.Errorf("failed to upgrade config file: %v", err)
+		}
+	}
+
+	return cfg, nil
 }
 
 // Save saves the configuration to the given path.
 // If no path is provided, it uses the default config path.
 func (c *Config) Save(path string) error {
-	if path == "" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return err
-		}
-		path = filepath.Join(homeDir, ".gotrack", "config.yaml")
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(path, data)
+}
+
+// DefaultPath returns the path of the config file chosen by the environment,
+// see ResolvePaths
+func DefaultPath() (string, error) {
+	paths, err := ResolvePaths("", "")
+	if err != nil {
+		return "", err
+	}
+	return paths.ConfigFile, nil
+}
 
-		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+// writeFile writes data to the config file at path, creating its directory
+// when needed. An empty path stands for the default config path.
+func writeFile(path string, data []byte) error {
+	if path == "" {
+		var err error
+		if path, err = DefaultPath(); err != nil {
 			return err
 		}
 	}
 
-	data, err := yaml.Marshal(c)
-	if err != nil {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return err
 	}
-
 	return os.WriteFile(path, data, 0644)
 }
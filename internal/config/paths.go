@@ -0,0 +1,89 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Environment variables that select where gotrack keeps its files
+const (
+	// EnvHome overrides the directory holding both the config and the data
+	EnvHome = "GOTRACK_HOME"
+	// EnvProfile selects a named profile
+	EnvProfile = "GOTRACK_PROFILE"
+)
+
+// Paths tells where gotrack keeps its config file and its data
+type Paths struct {
+	ConfigFile string
+	DataDir    string
+}
+
+// ResolvePaths returns the locations of the config file and the data
+// directory. They are looked up, in order, in:
+//
+//   - dataDir, e.g. from the --data-dir flag, holding both
+//   - $GOTRACK_HOME, holding both
+//   - ~/.gotrack, when it exists from earlier versions
+//   - $XDG_CONFIG_HOME/gotrack and $XDG_DATA_HOME/gotrack, which default to
+//     ~/.config/gotrack and ~/.local/share/gotrack
+//
+// A non-empty profile, or $GOTRACK_PROFILE, keeps its config and data in a
+// profiles/<name> subdirectory of each, separate from the default profile.
+func ResolvePaths(dataDir, profile string) (Paths, error) {
+	if profile == "" {
+		profile = os.Getenv(EnvProfile)
+	}
+	if strings.ContainsAny(profile, `/\`) || profile == "." || profile == ".." {
+		return Paths{}, fmt.Errorf("invalid profile name %q", profile)
+	}
+
+	if dataDir == "" {
+		dataDir = os.Getenv(EnvHome)
+	}
+
+	var configDir string
+	switch {
+	case dataDir != "":
+		configDir = dataDir
+	default:
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return Paths{}, fmt.Errorf("failed to get home directory: %v", err)
+		}
+
+		legacy := filepath.Join(homeDir, ".gotrack")
+		if _, err := os.Stat(legacy); err == nil {
+			configDir, dataDir = legacy, legacy
+			break
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return Paths{}, fmt.Errorf("failed to check %s: %v", legacy, err)
+		}
+
+		configDir = filepath.Join(xdgDir("XDG_CONFIG_HOME", homeDir, ".config"), "gotrack")
+		dataDir = filepath.Join(xdgDir("XDG_DATA_HOME", homeDir, ".local", "share"), "gotrack")
+	}
+
+	if profile != "" {
+		configDir = filepath.Join(configDir, "profiles", profile)
+		dataDir = filepath.Join(dataDir, "profiles", profile)
+	}
+
+	return Paths{
+		ConfigFile: filepath.Join(configDir, "config.yaml"),
+		DataDir:    dataDir,
+	}, nil
+}
+
+// xdgDir returns the directory named by the XDG environment variable, or
+// its default below the home directory. Relative paths are ignored, as the
+// XDG specification requires.
+func xdgDir(env, homeDir string, fallback ...string) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(append([]string{homeDir}, fallback...)...)
+}
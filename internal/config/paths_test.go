@@ -0,0 +1,91 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AndriyBarskyi/gotrack/internal/config"
+)
+
+func setupHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(config.EnvHome, "")
+	t.Setenv(config.EnvProfile, "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	return home
+}
+
+func TestResolvePaths(t *testing.T) {
+	t.Run("XDG defaults", func(t *testing.T) {
+		home := setupHome(t)
+
+		paths, err := config.ResolvePaths("", "")
+		require.NoError(t, err)
+		assert.Equal(t, config.Paths{
+			ConfigFile: filepath.Join(home, ".config", "gotrack", "config.yaml"),
+			DataDir:    filepath.Join(home, ".local", "share", "gotrack"),
+		}, paths)
+	})
+
+	t.Run("XDG variables", func(t *testing.T) {
+		setupHome(t)
+		t.Setenv("XDG_CONFIG_HOME", "/xdg/config")
+		t.Setenv("XDG_DATA_HOME", "relative/is/ignored")
+
+		paths, err := config.ResolvePaths("", "")
+		require.NoError(t, err)
+		assert.Equal(t, "/xdg/config/gotrack/config.yaml", paths.ConfigFile)
+		assert.Contains(t, paths.DataDir, filepath.Join(".local", "share", "gotrack"))
+	})
+
+	t.Run("existing ~/.gotrack", func(t *testing.T) {
+		home := setupHome(t)
+		require.NoError(t, os.Mkdir(filepath.Join(home, ".gotrack"), 0755))
+
+		paths, err := config.ResolvePaths("", "")
+		require.NoError(t, err)
+		assert.Equal(t, config.Paths{
+			ConfigFile: filepath.Join(home, ".gotrack", "config.yaml"),
+			DataDir:    filepath.Join(home, ".gotrack"),
+		}, paths)
+	})
+
+	t.Run("GOTRACK_HOME and data dir", func(t *testing.T) {
+		setupHome(t)
+		t.Setenv(config.EnvHome, "/env/home")
+
+		paths, err := config.ResolvePaths("", "")
+		require.NoError(t, err)
+		assert.Equal(t, config.Paths{ConfigFile: "/env/home/config.yaml", DataDir: "/env/home"}, paths)
+
+		paths, err = config.ResolvePaths("/flag/dir", "")
+		require.NoError(t, err)
+		assert.Equal(t, config.Paths{ConfigFile: "/flag/dir/config.yaml", DataDir: "/flag/dir"}, paths)
+	})
+
+	t.Run("profiles", func(t *testing.T) {
+		setupHome(t)
+		t.Setenv(config.EnvProfile, "personal")
+
+		paths, err := config.ResolvePaths("/data", "")
+		require.NoError(t, err)
+		assert.Equal(t, config.Paths{
+			ConfigFile: "/data/profiles/personal/config.yaml",
+			DataDir:    "/data/profiles/personal",
+		}, paths)
+
+		paths, err = config.ResolvePaths("/data", "work")
+		require.NoError(t, err)
+		assert.Equal(t, "/data/profiles/work", paths.DataDir)
+
+		_, err = config.ResolvePaths("/data", "../work")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectFileName is the name of the per-project config file
+const ProjectFileName = ".gotrack.yaml"
+
+// FindProjectFile looks for a .gotrack.yaml file in dir and its parents. It
+// returns the path of the closest one, or "" when there is none.
+func FindProjectFile(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		info, err := os.Stat(path)
+		switch {
+		case err == nil && !info.IsDir():
+			return path, nil
+		case err != nil && !errors.Is(err, os.ErrNotExist):
+			return "", fmt.Errorf("failed to check %s: %v", path, err)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// ApplyProjectFile layers the settings of the project file at path over the
+// configuration. Settings missing from the file keep their current values.
+// Hooks cannot be set by project files.
+// Invalid values are reported as a *ValidationError pointing to the lines of
+// the project file.
+func (c *Config) ApplyProjectFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read project config: %v", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse project config %s: %v", path, err)
+	}
+	if doc.Kind == 0 {
+		return nil
+	}
+	// Project files come with the repositories they are in, so they must
+	// not be able to run commands
+	if key, _ := mappingEntry(doc.Content[0], "hooks"); key != nil {
+		return fmt.Errorf("invalid project config %s:\n%w", path, &ValidationError{Errors: []FieldError{
+			{Field: "hooks", Line: key.Line, Message: "hooks can only be set in the user config"},
+		}})
+	}
+	if err := doc.Decode(c); err != nil {
+		return fmt.Errorf("failed to parse project config %s: %v", path, err)
+	}
+
+	if err := c.Validate(); err != nil {
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			verr.locate(&doc)
+		}
+		return fmt.Errorf("invalid project config %s:\n%w", path, err)
+	}
+	return nil
+}
+
+// templateSeparators are the characters around placeholders that are
+// dropped together with those that expand to nothing
+const templateSeparators = " -_/:.,"
+
+// placeholder matches a task template placeholder and the separators before it
+var placeholder = regexp.MustCompile(`[ \-_/:.,]*\{(project|dir|branch|date)\}`)
+
+// TaskName expands the task template for a project rooted at dir.
+// Placeholders that expand to nothing, such as {branch} outside a git
+// repository, are left out with the separators next to them. It returns ""
+// when no template is set or nothing is left.
+func (s *SessionConfig) TaskName(dir string, now time.Time) string {
+	if s.Task == "" {
+		return ""
+	}
+
+	project := s.Project
+	if project == "" {
+		project = filepath.Base(dir)
+	}
+	values := map[string]string{
+		"project": project,
+		"dir":     filepath.Base(dir),
+		"branch":  gitBranch(dir),
+		"date":    now.Format("2006-01-02"),
+	}
+	name := placeholder.ReplaceAllStringFunc(s.Task, func(m string) string {
+		i := strings.LastIndex(m, "{")
+		value := values[m[i+1:len(m)-1]]
+		if value == "" {
+			return ""
+		}
+		return m[:i] + value
+	})
+	return strings.Trim(name, templateSeparators)
+}
+
+// gitBranch returns the branch checked out in the git repository at dir, the
+// short commit when HEAD is detached, or "" when dir is not a repository
+func gitBranch(dir string) string {
+	gitDir := filepath.Join(dir, ".git")
+	// In worktrees and submodules .git is a file pointing to the real directory
+	if data, err := os.ReadFile(gitDir); err == nil {
+		if target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: "); ok {
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(dir, target)
+			}
+			gitDir = target
+		}
+	}
+
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+	head = bytes.TrimSpace(head)
+	if branch, ok := bytes.CutPrefix(head, []byte("ref: refs/heads/")); ok {
+		return string(branch)
+	}
+	return string(head[:min(len(head), 7)])
+}
@@ -0,0 +1,120 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AndriyBarskyi/gotrack/internal/config"
+)
+
+func TestFindProjectFile(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "repo", "internal", "pkg")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+
+	path, err := config.FindProjectFile(nested)
+	require.NoError(t, err)
+	assert.Empty(t, path)
+
+	projectFile := filepath.Join(root, "repo", config.ProjectFileName)
+	require.NoError(t, os.WriteFile(projectFile, []byte("session:\n  project: repo\n"), 0644))
+
+	path, err = config.FindProjectFile(nested)
+	require.NoError(t, err)
+	assert.Equal(t, projectFile, path)
+}
+
+func TestApplyProjectFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), config.ProjectFileName)
+	data := `session:
+  project: gotrack
+  tags: [go, oss]
+pomodoro:
+  work_duration: 50m
+`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+
+	cfg := config.Default()
+	cfg.Pomodoro.BreakDuration = config.Duration(10 * time.Minute)
+	require.NoError(t, cfg.ApplyProjectFile(path))
+
+	assert.Equal(t, "gotrack", cfg.Session.Project)
+	assert.Equal(t, []string{"go", "oss"}, cfg.Session.Tags)
+	assert.Equal(t, config.Duration(50*time.Minute), cfg.Pomodoro.WorkDuration)
+	assert.Equal(t, config.Duration(10*time.Minute), cfg.Pomodoro.BreakDuration, "User settings missing from the project file should be kept")
+}
+
+func TestApplyProjectFile_Invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name  string
+		data  string
+		field string
+		line  int
+	}{
+		{name: "invalid value", data: "session:\n  project: x\npomodoro:\n  long_break_interval: 0\n", field: "pomodoro.long_break_interval", line: 4},
+		{name: "hooks", data: "hooks:\n  events:\n    session.started: [\"rm -rf ~\"]\n", field: "hooks", line: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, config.ProjectFileName)
+			require.NoError(t, os.WriteFile(path, []byte(tt.data), 0644))
+
+			err := config.Default().ApplyProjectFile(path)
+			var verr *config.ValidationError
+			require.True(t, errors.As(err, &verr))
+			assert.Equal(t, tt.field, verr.Errors[0].Field)
+			assert.Equal(t, tt.line, verr.Errors[0].Line)
+		})
+	}
+}
+
+func TestSessionConfig_TaskName(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "myrepo")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref: refs/heads/feature/x\n"), 0644))
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	s := config.SessionConfig{Task: "{project}: {branch} ({dir}, {date})"}
+	assert.Equal(t, "myrepo: feature/x (myrepo, 2026-03-02)", s.TaskName(dir, now))
+
+	s.Project = "gotrack"
+	assert.Equal(t, "gotrack: feature/x (myrepo, 2026-03-02)", s.TaskName(dir, now))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("0123abcd4567\n"), 0644))
+	assert.Equal(t, "gotrack: 0123abc", (&config.SessionConfig{Project: "gotrack", Task: "{project}: {branch}"}).TaskName(dir, now),
+		"A detached HEAD gives the short commit")
+
+	assert.Empty(t, (&config.SessionConfig{}).TaskName(dir, now))
+}
+
+func TestSessionConfig_TaskName_NotRepository(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "notes")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	now := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{template: "{project}-{branch}-{date}", expected: "notes-2026-10-18"},
+		{template: "{project}: {branch}", expected: "notes"},
+		{template: "{branch}/{dir}", expected: "notes"},
+		{template: "{branch} - {branch}", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			s := config.SessionConfig{Task: tt.template}
+			assert.Equal(t, tt.expected, s.TaskName(dir, now))
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FieldError describes an invalid configuration value
+type FieldError struct {
+	// Field is the YAML path of the value, e.g. "pomodoro.work_duration"
+	Field string
+	// Line is the line of the value in the config file, zero when unknown
+	Line    int
+	Message string
+}
+
+// Error implements error
+func (e FieldError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s: %s", e.Line, e.Field, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationError lists every invalid value of a configuration
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Error implements error
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// validator collects field errors
+type validator struct {
+	errs []FieldError
+}
+
+func (v *validator) check(ok bool, field, format string, args ...any) {
+	if !ok {
+		v.errs = append(v.errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+// Validate checks that every setting holds a usable value. It returns a
+// *ValidationError listing all invalid fields, or nil.
+func (c *Config) Validate() error {
+	v := &validator{}
+	c.Pomodoro.validate(v, "pomodoro")
+	c.Hooks.validate(v, "hooks")
+	c.Reports.validate(v, "reports")
+	for _, name := range sortedKeys(c.Budgets) {
+		b := c.Budgets[name]
+		b.validate(v, "budgets."+name)
+	}
+	c.Timesheet.validate(v, "timesheet")
+	c.Standup.validate(v, "standup")
+
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: v.errs}
+}
+
+func (p *PomodoroConfig) validate(v *validator, path string) {
+	v.check(p.WorkDuration > 0, path+".work_duration", "must be positive, got %s", p.WorkDuration)
+	v.check(p.BreakDuration > 0, path+".break_duration", "must be positive, got %s", p.BreakDuration)
+	v.check(p.LongBreak > 0, path+".long_break", "must be positive, got %s", p.LongBreak)
+	v.check(p.LongBreakInterval > 0, path+".long_break_interval", "must be at least 1, got %d", p.LongBreakInterval)
+	v.check(p.DailyGoal >= 0, path+".daily_pomodoro_goal", "cannot be negative, got %d", p.DailyGoal)
+
+	_, custom := p.Sequences[p.Mode]
+	_, _, ratio := ParseRatio(p.Mode)
+	v.check(custom || p.Mode == "" || p.Mode == ModeClassic || p.Mode == ModeFlowtime || ratio,
+		path+".mode", "unknown timer mode %q, expected classic, flowtime, a ratio like 52/17 or a name from sequences", p.Mode)
+
+	backend := p.Notifications.Backend
+	v.check(backend == "" || slices.Contains(NotificationBackends, backend), path+".notifications.backend",
+		"unknown backend %q, expected one of %s", backend, strings.Join(NotificationBackends, ", "))
+
+	ft := p.Flowtime
+	v.check(ft.BreakRatio > 0, path+".flowtime.break_ratio", "must be positive, got %g", ft.BreakRatio)
+	v.check(ft.MinBreak > 0, path+".flowtime.min_break", "must be positive, got %s", ft.MinBreak)
+	v.check(ft.MaxBreak >= 0, path+".flowtime.max_break", "cannot be negative, got %s", ft.MaxBreak)
+	v.check(ft.MaxBreak == 0 || ft.MaxBreak >= ft.MinBreak, path+".flowtime.max_break",
+		"must not be shorter than min_break (%s), got %s", ft.MinBreak, ft.MaxBreak)
+
+	for _, name := range sortedKeys(p.Sequences) {
+		phases := p.Sequences[name]
+		seqPath := path + ".sequences." + name
+		v.check(len(phases) > 0, seqPath, "must have at least one phase")
+		for i, ph := range phases {
+			phasePath := fmt.Sprintf("%s[%d]", seqPath, i)
+			v.check(ph.Kind == "work" || ph.Kind == "break", phasePath+".kind", "must be \"work\" or \"break\", got %q", ph.Kind)
+			v.check(ph.Duration > 0, phasePath+".duration", "must be positive, got %s", ph.Duration)
+		}
+	}
+}
+
+func (h *HooksConfig) validate(v *validator, path string) {
+	v.check(h.Timeout > 0, path+".timeout", "must be positive, got %s", h.Timeout)
+	for _, event := range sortedKeys(h.Events) {
+		for i, command := range h.Events[event] {
+			v.check(strings.TrimSpace(command) != "", fmt.Sprintf("%s.events.%s[%d]", path, event, i), "command cannot be empty")
+		}
+	}
+}
+
+func (r *ReportsConfig) validate(v *validator, path string) {
+	_, err := r.Location()
+	v.check(err == nil, path+".timezone", "unknown timezone %q", r.Timezone)
+	v.check(r.DayStartHour >= 0 && r.DayStartHour <= 23, path+".day_start_hour", "must be between 0 and 23, got %d", r.DayStartHour)
+
+	sc, scPath := r.Score, path+".score"
+	v.check(sc.WindowDays > 0, scPath+".window_days", "must be at least 1, got %d", sc.WindowDays)
+	v.check(sc.VolumeTarget > 0, scPath+".volume_target", "must be positive, got %s", sc.VolumeTarget)
+	v.check(sc.ActiveDaysTarget > 0, scPath+".active_days_target", "must be at least 1, got %d", sc.ActiveDaysTarget)
+	v.check(sc.StreakTarget > 0, scPath+".streak_target", "must be at least 1, got %d", sc.StreakTarget)
+	w := sc.Weights
+	v.check(w.Volume >= 0, scPath+".weights.volume", "cannot be negative, got %g", w.Volume)
+	v.check(w.Consistency >= 0, scPath+".weights.consistency", "cannot be negative, got %g", w.Consistency)
+	v.check(w.Streak >= 0, scPath+".weights.streak", "cannot be negative, got %g", w.Streak)
+	v.check(w.Volume+w.Consistency+w.Streak > 0, scPath+".weights", "at least one weight must be positive")
+}
+
+func (b *BudgetConfig) validate(v *validator, path string) {
+	selectors := 0
+	for _, s := range []string{b.Task, b.Tag, b.Project} {
+		if s != "" {
+			selectors++
+		}
+	}
+	v.check(selectors == 1, path, "must set exactly one of task, tag or project")
+	v.check(b.Limit > 0, path+".limit", "must be positive, got %s", b.Limit)
+	v.check(slices.Contains(BudgetPeriods, b.Period), path+".period",
+		"unknown period %q, expected one of %s", b.Period, strings.Join(BudgetPeriods, ", "))
+}
+
+func (t *TimesheetConfig) validate(v *validator, path string) {
+	v.check(slices.Contains(TimesheetRoundings, t.Rounding), path+".rounding",
+		"unknown rounding %q, expected one of %s", t.Rounding, strings.Join(TimesheetRoundings, ", "))
+	v.check(slices.Contains(TimesheetIncrements, t.Increment), path+".increment",
+		"must be 6m, 15m or 30m, got %s", t.Increment)
+	v.check(slices.Contains(TimesheetRoundPer, t.RoundPer), path+".round_per",
+		"unknown entry %q, expected one of %s", t.RoundPer, strings.Join(TimesheetRoundPer, ", "))
+	v.check(t.Minimum >= 0, path+".minimum", "cannot be negative, got %s", t.Minimum)
+}
+
+func (s *StandupConfig) validate(v *validator, path string) {
+	v.check(len(s.WorkingDays) > 0, path+".working_days", "must list at least one day")
+	for _, day := range s.WorkingDays {
+		_, ok := weekdays[strings.ToLower(day)]
+		v.check(ok, path+".working_days", "unknown day %q, expected e.g. monday", day)
+	}
+	for _, date := range s.Holidays {
+		_, err := time.Parse(time.DateOnly, date)
+		v.check(err == nil, path+".holidays", "invalid date %q, expected YYYY-MM-DD", date)
+	}
+}
+
+// sortedKeys returns the keys of m in order, so errors are reported the same
+// way every time
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// locate fills in the YAML line of every field error from the parsed
+// document. A field that is missing from the file points at the closest
+// parent that is present.
+func (e *ValidationError) locate(doc *yaml.Node) {
+	for i := range e.Errors {
+		e.Errors[i].Line = lineOf(doc, e.Errors[i].Field)
+	}
+}
+
+// lineOf returns the line of the deepest node found along the dotted path,
+// or zero when not even the first segment is present
+func lineOf(doc *yaml.Node, path string) int {
+	node := doc
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	line := 0
+	for _, segment := range strings.Split(path, ".") {
+		key, rest, _ := strings.Cut(segment, "[")
+		keyNode, next := mappingEntry(node, key)
+		if next == nil {
+			return line
+		}
+		line, node = keyNode.Line, next
+
+		if rest != "" {
+			idx, err := strconv.Atoi(strings.TrimSuffix(rest, "]"))
+			if err != nil || node.Kind != yaml.SequenceNode || idx >= len(node.Content) {
+				return line
+			}
+			node = node.Content[idx]
+			line = node.Line
+		}
+	}
+	return line
+}
+
+// mappingEntry returns the key and value nodes stored under key in a mapping
+// node, or nils when there is no such key
+func mappingEntry(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
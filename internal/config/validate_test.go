@@ -0,0 +1,205 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AndriyBarskyi/gotrack/internal/config"
+)
+
+func TestValidate_Default(t *testing.T) {
+	assert.NoError(t, config.Default().Validate())
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*config.Config)
+		fields []string
+	}{
+		{
+			name: "non-positive durations",
+			modify: func(c *config.Config) {
+				c.Pomodoro.WorkDuration = 0
+				c.Pomodoro.BreakDuration = config.Duration(-time.Minute)
+				c.Hooks.Timeout = 0
+			},
+			fields: []string{"pomodoro.work_duration", "pomodoro.break_duration", "hooks.timeout"},
+		},
+		{
+			name:   "zero long break interval",
+			modify: func(c *config.Config) { c.Pomodoro.LongBreakInterval = 0 },
+			fields: []string{"pomodoro.long_break_interval"},
+		},
+		{
+			name:   "unknown mode",
+			modify: func(c *config.Config) { c.Pomodoro.Mode = "52/0" },
+			fields: []string{"pomodoro.mode"},
+		},
+		{
+			name: "ratio and custom modes",
+			modify: func(c *config.Config) {
+				c.Pomodoro.Mode = "52/17"
+				c.Pomodoro.Sequences = map[string][]config.PhaseConfig{
+					"writing": {{Kind: "work", Duration: config.Duration(time.Hour)}},
+				}
+			},
+		},
+		{
+			name: "invalid sequence phases",
+			modify: func(c *config.Config) {
+				c.Pomodoro.Sequences = map[string][]config.PhaseConfig{
+					"empty":   {},
+					"writing": {{Kind: "work", Duration: config.Duration(time.Hour)}, {Kind: "nap"}},
+				}
+			},
+			fields: []string{
+				"pomodoro.sequences.empty",
+				"pomodoro.sequences.writing[1].kind",
+				"pomodoro.sequences.writing[1].duration",
+			},
+		},
+		{
+			name: "flowtime and notifications",
+			modify: func(c *config.Config) {
+				c.Pomodoro.Flowtime.BreakRatio = 0
+				c.Pomodoro.Flowtime.MaxBreak = config.Duration(time.Second)
+				c.Pomodoro.Notifications.Backend = "pigeon"
+			},
+			fields: []string{
+				"pomodoro.notifications.backend",
+				"pomodoro.flowtime.break_ratio",
+				"pomodoro.flowtime.max_break",
+			},
+		},
+		{
+			name:   "zero flowtime minimum break",
+			modify: func(c *config.Config) { c.Pomodoro.Flowtime.MinBreak = 0 },
+			fields: []string{"pomodoro.flowtime.min_break"},
+		},
+		{
+			name: "reports",
+			modify: func(c *config.Config) {
+				c.Reports.Timezone = "Mars/Olympus_Mons"
+				c.Reports.DayStartHour = 24
+			},
+			fields: []string{"reports.timezone", "reports.day_start_hour"},
+		},
+		{
+			name: "reports timezone and day start",
+			modify: func(c *config.Config) {
+				c.Reports.Timezone = "Europe/Kyiv"
+				c.Reports.DayStartHour = 4
+			},
+		},
+		{
+			name: "timesheet",
+			modify: func(c *config.Config) {
+				c.Timesheet = config.TimesheetConfig{Rounding: "ceil", Increment: config.Duration(10 * time.Minute), RoundPer: "week", Minimum: -1}
+			},
+			fields: []string{"timesheet.rounding", "timesheet.increment", "timesheet.round_per", "timesheet.minimum"},
+		},
+		{
+			name: "standup",
+			modify: func(c *config.Config) {
+				c.Standup = config.StandupConfig{WorkingDays: []string{"Monday", "funday"}, Holidays: []string{"2026-12-25", "25/12/2026"}}
+			},
+			fields: []string{"standup.working_days", "standup.holidays"},
+		},
+		{
+			name: "score",
+			modify: func(c *config.Config) {
+				c.Reports.Score.WindowDays = 0
+				c.Reports.Score.StreakTarget = -1
+				c.Reports.Score.Weights = config.ScoreWeights{Volume: -1}
+			},
+			fields: []string{
+				"reports.score.window_days",
+				"reports.score.streak_target",
+				"reports.score.weights.volume",
+				"reports.score.weights",
+			},
+		},
+		{
+			name: "budgets",
+			modify: func(c *config.Config) {
+				c.Budgets = map[string]config.BudgetConfig{
+					"client-a": {Project: "client-a", Limit: config.Duration(40 * time.Hour), Period: "month"},
+					"both":     {Task: "email", Tag: "admin", Limit: config.Duration(time.Hour), Period: "day"},
+					"none":     {Period: "fortnight"},
+				}
+			},
+			fields: []string{"budgets.both", "budgets.none", "budgets.none.limit", "budgets.none.period"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if len(tt.fields) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var verr *config.ValidationError
+			require.True(t, errors.As(err, &verr))
+			var fields []string
+			for _, fe := range verr.Errors {
+				fields = append(fields, fe.Field)
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+}
+
+func TestLoad_ReportsLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `pomodoro:
+  work_duration: 25m
+  long_break_interval: 0
+  sequences:
+    writing:
+      - {kind: work, duration: 45m}
+      - {kind: nap, duration: 10m}
+hooks:
+  timeout: 5s
+`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+
+	_, err := config.Load(path)
+	var verr *config.ValidationError
+	require.True(t, errors.As(err, &verr))
+	assert.Equal(t, []config.FieldError{
+		{Field: "pomodoro.long_break_interval", Line: 3, Message: "must be at least 1, got 0"},
+		{Field: "pomodoro.sequences.writing[1].kind", Line: 7, Message: `must be "work" or "break", got "nap"`},
+	}, verr.Errors)
+	assert.Contains(t, err.Error(), "line 3: pomodoro.long_break_interval")
+}
+
+func TestLoad_MissingFieldPointsToParent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `hooks:
+  events: {}
+pomodoro:
+  flowtime:
+    min_break: 40m
+`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+
+	_, err := config.Load(path)
+	var verr *config.ValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Len(t, verr.Errors, 1)
+	// max_break keeps its 30m default, which is shorter than min_break
+	assert.Equal(t, "pomodoro.flowtime.max_break", verr.Errors[0].Field)
+	assert.Equal(t, 4, verr.Errors[0].Line)
+}
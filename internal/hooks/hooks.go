@@ -0,0 +1,177 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AndriyBarskyi/gotrack/internal/config"
+)
+
+// Event names that hooks can be registered for
+const (
+	SessionStarted  = "session.started"
+	SessionFinished = "session.finished"
+
+	PomodoroWorkStarted       = "pomodoro.work_started"
+	PomodoroShortBreakStarted = "pomodoro.short_break_started"
+	PomodoroLongBreakStarted  = "pomodoro.long_break_started"
+	PomodoroBreakStarted      = "pomodoro.break_started"
+	PomodoroPaused            = "pomodoro.paused"
+	PomodoroStopped           = "pomodoro.stopped"
+)
+
+// DefaultTimeout is used when the configured hook timeout is not positive
+const DefaultTimeout = 10 * time.Second
+
+// Event describes something that happened in gotrack
+type Event struct {
+	Name string            `json:"event"`
+	Time time.Time         `json:"time"`
+	Task string            `json:"task,omitempty"`
+	Data map[string]string `json:"data,omitempty"`
+}
+
+// Runner runs the shell commands configured for events
+type Runner struct {
+	events  map[string][]string
+	timeout time.Duration
+	errOut  io.Writer
+
+	mu sync.Mutex
+	// last is closed once the hooks of the last event fired have run
+	last chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewRunner creates a Runner from the hooks configuration.
+// Hook failures are written to errOut; a nil errOut discards them.
+func NewRunner(cfg config.HooksConfig, errOut io.Writer) *Runner {
+	timeout := time.Duration(cfg.Timeout)
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	if errOut == nil {
+		errOut = io.Discard
+	}
+	return &Runner{
+		events:  cfg.Events,
+		timeout: timeout,
+		errOut:  errOut,
+	}
+}
+
+// Fire runs every command registered for the event, one after another, in
+// the background so that a slow hook never holds up the caller. Events are
+// handled in the order they are fired. Each command gets the event as JSON on
+// stdin and as GOTRACK_HOOK_* environment variables. Failures and timeouts are
+// logged and never returned, so a broken hook cannot stop time tracking.
+func (r *Runner) Fire(ev Event) {
+	if r == nil {
+		return
+	}
+	commands := r.events[ev.Name]
+	if len(commands) == 0 {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		fmt.Fprintf(r.errOut, "hook %s: failed to encode event: %v\n", ev.Name, err)
+		return
+	}
+	env := append(os.Environ(), environ(ev)...)
+
+	r.mu.Lock()
+	prev, done := r.last, make(chan struct{})
+	r.last = done
+	r.wg.Add(1)
+	r.mu.Unlock()
+
+	go func() {
+		defer r.wg.Done()
+		defer close(done)
+		if prev != nil {
+			<-prev
+		}
+		for _, command := range commands {
+			if err := r.run(command, env, payload); err != nil {
+				fmt.Fprintf(r.errOut, "hook %s: %q failed: %v\n", ev.Name, command, err)
+			}
+		}
+	}()
+}
+
+// Wait blocks until the hooks of every event fired so far have run. It is
+// called before exiting so that hooks are not cut short.
+func (r *Runner) Wait() {
+	if r == nil {
+		return
+	}
+	r.wg.Wait()
+}
+
+func (r *Runner) run(command string, env []string, payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, command)
+	cmd.Env = env
+	cmd.Stdin = bytes.NewReader(payload)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	// Children of the shell may keep stderr open after it is killed
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", r.timeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// environ returns the GOTRACK_HOOK_* variables describing the event.
+// Data keys are upper-cased, e.g. "state" becomes GOTRACK_HOOK_STATE.
+func environ(ev Event) []string {
+	env := []string{
+		config.HookEnvPrefix + "EVENT=" + ev.Name,
+		config.HookEnvPrefix + "TIME=" + ev.Time.Format(time.RFC3339),
+		config.HookEnvPrefix + "TASK=" + ev.Task,
+	}
+
+	keys := make([]string, 0, len(ev.Data))
+	for k := range ev.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		name := strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(k))
+		env = append(env, config.HookEnvPrefix+name+"="+ev.Data[k])
+	}
+	return env
+}
@@ -0,0 +1,157 @@
+package hooks_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AndriyBarskyi/gotrack/internal/config"
+	"github.com/AndriyBarskyi/gotrack/internal/hooks"
+)
+
+func skipOnWindows(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook tests use a POSIX shell")
+	}
+}
+
+func TestRunner_Fire(t *testing.T) {
+	skipOnWindows(t)
+
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, "env")
+	stdinFile := filepath.Join(dir, "stdin")
+
+	runner := hooks.NewRunner(config.HooksConfig{
+		Events: map[string][]string{
+			hooks.PomodoroWorkStarted: {
+				`echo "$GOTRACK_HOOK_EVENT|$GOTRACK_HOOK_TASK|$GOTRACK_HOOK_STATE" > ` + envFile,
+				`cat > ` + stdinFile,
+			},
+		},
+	}, nil)
+
+	ev := hooks.Event{
+		Name: hooks.PomodoroWorkStarted,
+		Time: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+		Task: "Deep work",
+		Data: map[string]string{"state": "working"},
+	}
+	runner.Fire(ev)
+	runner.Wait()
+
+	env, err := os.ReadFile(envFile)
+	require.NoError(t, err)
+	assert.Equal(t, "pomodoro.work_started|Deep work|working", strings.TrimSpace(string(env)))
+
+	stdin, err := os.ReadFile(stdinFile)
+	require.NoError(t, err)
+	var got hooks.Event
+	require.NoError(t, json.Unmarshal(stdin, &got))
+	assert.Equal(t, ev.Name, got.Name)
+	assert.Equal(t, ev.Task, got.Task)
+	assert.True(t, ev.Time.Equal(got.Time))
+	assert.Equal(t, ev.Data, got.Data)
+}
+
+func TestRunner_Fire_EnvIsNotConfig(t *testing.T) {
+	skipOnWindows(t)
+
+	envFile := filepath.Join(t.TempDir(), "env")
+	runner := hooks.NewRunner(config.HooksConfig{
+		Events: map[string][]string{
+			hooks.SessionStarted: {"env > " + envFile},
+		},
+	}, nil)
+
+	// A detail named like a setting must not override it when the hook
+	// calls gotrack
+	runner.Fire(hooks.Event{
+		Name: hooks.SessionStarted,
+		Task: "coding",
+		Data: map[string]string{"pomodoro.work_duration": "soon"},
+	})
+	runner.Wait()
+
+	env, err := os.ReadFile(envFile)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(env)), "\n")
+	assert.Contains(t, lines, "GOTRACK_HOOK_TASK=coding")
+	assert.Contains(t, lines, "GOTRACK_HOOK_POMODORO_WORK_DURATION=soon")
+
+	applied, err := config.Default().ApplyEnv(lines)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+}
+
+func TestRunner_Fire_Failures(t *testing.T) {
+	skipOnWindows(t)
+
+	t.Run("failing command is logged", func(t *testing.T) {
+		var log bytes.Buffer
+		runner := hooks.NewRunner(config.HooksConfig{
+			Events: map[string][]string{
+				hooks.SessionStarted: {"echo broken >&2; exit 3"},
+			},
+		}, &log)
+
+		runner.Fire(hooks.Event{Name: hooks.SessionStarted})
+		runner.Wait()
+		assert.Contains(t, log.String(), "hook session.started")
+		assert.Contains(t, log.String(), "broken")
+	})
+
+	t.Run("slow command times out", func(t *testing.T) {
+		var log bytes.Buffer
+		runner := hooks.NewRunner(config.HooksConfig{
+			Timeout: config.Duration(100 * time.Millisecond),
+			Events: map[string][]string{
+				hooks.SessionFinished: {"sleep 5"},
+			},
+		}, &log)
+
+		start := time.Now()
+		runner.Fire(hooks.Event{Name: hooks.SessionFinished})
+		runner.Wait()
+		assert.Less(t, time.Since(start), 2*time.Second)
+		assert.Contains(t, log.String(), "timed out")
+	})
+
+	t.Run("unregistered event is ignored", func(t *testing.T) {
+		var log bytes.Buffer
+		runner := hooks.NewRunner(config.HooksConfig{}, &log)
+		runner.Fire(hooks.Event{Name: hooks.PomodoroStopped})
+		runner.Wait()
+		assert.Empty(t, log.String())
+	})
+}
+
+func TestRunner_Fire_Background(t *testing.T) {
+	skipOnWindows(t)
+
+	out := filepath.Join(t.TempDir(), "out")
+	runner := hooks.NewRunner(config.HooksConfig{
+		Events: map[string][]string{
+			hooks.PomodoroWorkStarted:  {"sleep 0.5; echo work >> " + out},
+			hooks.PomodoroBreakStarted: {"echo break >> " + out},
+		},
+	}, nil)
+
+	start := time.Now()
+	runner.Fire(hooks.Event{Name: hooks.PomodoroWorkStarted})
+	runner.Fire(hooks.Event{Name: hooks.PomodoroBreakStarted})
+	assert.Less(t, time.Since(start), 250*time.Millisecond, "Fire should not wait for the hooks")
+
+	runner.Wait()
+	got, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "work\nbreak\n", string(got), "Hooks should run in the order their events were fired")
+}
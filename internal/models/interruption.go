@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Interruption represents something that broke the focus of a pomodoro work interval
+type Interruption struct {
+	Task string `json:"task"`
+	// IntervalStart is the start time of the work interval that was interrupted
+	IntervalStart time.Time `json:"interval_start"`
+	Time          time.Time `json:"time"`
+	// External is true for interruptions caused by others, false for internal ones
+	External bool   `json:"external"`
+	Reason   string `json:"reason,omitempty"`
+	// Void is true when the interrupted work interval was abandoned and restarted
+	Void bool `json:"void,omitempty"`
+}
+
+// Kind returns "external" or "internal"
+func (i *Interruption) Kind() string {
+	if i.External {
+		return "external"
+	}
+	return "internal"
+}
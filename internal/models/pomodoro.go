@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Pomodoro represents a single pomodoro work interval
+type Pomodoro struct {
+	Task      string    `json:"task"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	// Planned is the length the interval was meant to run, zero for
+	// open-ended intervals
+	Planned time.Duration `json:"planned"`
+	// Completed is false when the interval was skipped, voided or stopped
+	// before it ran its course
+	Completed bool `json:"completed"`
+}
+
+// Duration returns how long the interval lasted
+func (p *Pomodoro) Duration() time.Duration {
+	return p.EndTime.Sub(p.StartTime)
+}
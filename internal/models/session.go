@@ -11,8 +11,12 @@ var ErrNoSessions = errors.New("no sessions found")
 // Session represents a work session
 type Session struct {
 	Task      string    `json:"task"`
+	Project   string    `json:"project,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
 	StartTime time.Time `json:"start_time"`
 	EndTime   time.Time `json:"end_time"`
+	// Note describes what was done in the session
+	Note string `json:"note,omitempty"`
 }
 
 // IsActive returns true if the session is currently active (started but not finished)
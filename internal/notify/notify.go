@@ -0,0 +1,177 @@
+package notify
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/AndriyBarskyi/gotrack/internal/config"
+)
+
+// Backend names accepted in the notifications config
+const (
+	BackendAuto   = config.BackendAuto
+	BackendDBus   = config.BackendDBus
+	BackendBell   = config.BackendBell
+	BackendOSC9   = config.BackendOSC9
+	BackendOSC777 = config.BackendOSC777
+	BackendNone   = config.BackendNone
+)
+
+// appName is the application name shown by notification daemons
+const appName = "gotrack"
+
+// Notifier delivers a notification to the user
+type Notifier interface {
+	Notify(title, message string) error
+}
+
+// New creates the Notifier selected by the configuration.
+// Terminal based backends write their escape sequences to term.
+func New(cfg config.NotificationsConfig, term io.Writer) (Notifier, error) {
+	if !cfg.Enabled {
+		return Noop{}, nil
+	}
+
+	switch cfg.Backend {
+	case "", BackendAuto:
+		return detect(term), nil
+	case BackendDBus:
+		return DBus{}, nil
+	case BackendBell:
+		return Bell{Out: term}, nil
+	case BackendOSC9:
+		return OSC9{Out: term}, nil
+	case BackendOSC777:
+		return OSC777{Out: term}, nil
+	case BackendNone:
+		return Noop{}, nil
+	default:
+		return nil, fmt.Errorf("unknown notification backend %q", cfg.Backend)
+	}
+}
+
+// detect picks D-Bus when a session bus is reachable and falls back to the
+// terminal bell otherwise
+func detect(term io.Writer) Notifier {
+	if runtime.GOOS != "windows" && runtime.GOOS != "darwin" && os.Getenv("DBUS_SESSION_BUS_ADDRESS") != "" {
+		if _, err := exec.LookPath("gdbus"); err == nil {
+			return DBus{}
+		}
+	}
+	return Bell{Out: term}
+}
+
+// Noop discards all notifications
+type Noop struct{}
+
+// Notify does nothing
+func (Noop) Notify(title, message string) error {
+	return nil
+}
+
+// DBus sends notifications to the freedesktop notification daemon
+// through the gdbus command line tool
+type DBus struct{}
+
+// Notify calls org.freedesktop.Notifications.Notify on the session bus
+func (DBus) Notify(title, message string) error {
+	cmd := exec.Command("gdbus", "call", "--session",
+		"--dest", "org.freedesktop.Notifications",
+		"--object-path", "/org/freedesktop/Notifications",
+		"--method", "org.freedesktop.Notifications.Notify",
+		gvariantString(appName), "0", "''",
+		gvariantString(title), gvariantString(message),
+		"[]", "{}", "-1",
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("dbus notification failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// gvariantString quotes s in the GVariant text format expected by gdbus
+func gvariantString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// Bell rings the terminal bell
+type Bell struct {
+	Out io.Writer
+}
+
+// Notify writes the BEL character
+func (b Bell) Notify(title, message string) error {
+	_, err := io.WriteString(b.Out, "\a")
+	return err
+}
+
+// OSC9 sends notifications with the OSC 9 escape sequence understood by
+// iTerm2, Windows Terminal, WezTerm and others
+type OSC9 struct {
+	Out io.Writer
+}
+
+// Notify writes the OSC 9 sequence for the notification
+func (o OSC9) Notify(title, message string) error {
+	text := sanitize(title)
+	if message != "" {
+		text += ": " + sanitize(message)
+	}
+	_, err := fmt.Fprintf(o.Out, "\x1b]9;%s\a", text)
+	return err
+}
+
+// OSC777 sends notifications with the OSC 777 escape sequence understood by
+// urxvt, foot, Ghostty and others
+type OSC777 struct {
+	Out io.Writer
+}
+
+// Notify writes the OSC 777 sequence for the notification
+func (o OSC777) Notify(title, message string) error {
+	title = strings.ReplaceAll(sanitize(title), ";", ",")
+	_, err := fmt.Fprintf(o.Out, "\x1b]777;notify;%s;%s\a", title, sanitize(message))
+	return err
+}
+
+// sanitize drops control characters that would end an escape sequence early
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// Notification is a notification captured by a Recorder
+type Notification struct {
+	Title   string
+	Message string
+}
+
+// Recorder is a Notifier that records notifications instead of showing them
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Notification
+}
+
+// Notify records the notification
+func (r *Recorder) Notify(title, message string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Notification{Title: title, Message: message})
+	return nil
+}
+
+// Calls returns the notifications recorded so far
+func (r *Recorder) Calls() []Notification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Notification(nil), r.calls...)
+}
@@ -0,0 +1,109 @@
+package notify_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AndriyBarskyi/gotrack/internal/config"
+	"github.com/AndriyBarskyi/gotrack/internal/notify"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.NotificationsConfig
+		expected    notify.Notifier
+		expectError bool
+	}{
+		{
+			name:     "disabled",
+			cfg:      config.NotificationsConfig{Enabled: false, Backend: notify.BackendBell},
+			expected: notify.Noop{},
+		},
+		{
+			name:     "none backend",
+			cfg:      config.NotificationsConfig{Enabled: true, Backend: notify.BackendNone},
+			expected: notify.Noop{},
+		},
+		{
+			name:     "dbus backend",
+			cfg:      config.NotificationsConfig{Enabled: true, Backend: notify.BackendDBus},
+			expected: notify.DBus{},
+		},
+		{
+			name:     "osc9 backend",
+			cfg:      config.NotificationsConfig{Enabled: true, Backend: notify.BackendOSC9},
+			expected: notify.OSC9{},
+		},
+		{
+			name:        "unknown backend",
+			cfg:         config.NotificationsConfig{Enabled: true, Backend: "pigeon"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := notify.New(tt.cfg, nil)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.IsType(t, tt.expected, n)
+		})
+	}
+}
+
+func TestNew_ConfigBackends(t *testing.T) {
+	for _, backend := range config.NotificationBackends {
+		_, err := notify.New(config.NotificationsConfig{Enabled: true, Backend: backend}, nil)
+		assert.NoError(t, err, "Every backend accepted by the config should be implemented: %s", backend)
+	}
+}
+
+func TestTerminalBackends(t *testing.T) {
+	tests := []struct {
+		name     string
+		notifier func(*bytes.Buffer) notify.Notifier
+		expected string
+	}{
+		{
+			name:     "bell",
+			notifier: func(b *bytes.Buffer) notify.Notifier { return notify.Bell{Out: b} },
+			expected: "\a",
+		},
+		{
+			name:     "osc9",
+			notifier: func(b *bytes.Buffer) notify.Notifier { return notify.OSC9{Out: b} },
+			expected: "\x1b]9;Break time: Take a 5m break\a",
+		},
+		{
+			name:     "osc777",
+			notifier: func(b *bytes.Buffer) notify.Notifier { return notify.OSC777{Out: b} },
+			expected: "\x1b]777;notify;Break time;Take a 5m break\a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := tt.notifier(&buf).Notify("Break time", "Take a 5m break\x1b")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	rec := &notify.Recorder{}
+	assert.NoError(t, rec.Notify("a", "b"))
+	assert.NoError(t, rec.Notify("c", ""))
+	assert.Equal(t, []notify.Notification{
+		{Title: "a", Message: "b"},
+		{Title: "c"},
+	}, rec.Calls())
+}
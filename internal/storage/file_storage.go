@@ -17,10 +17,32 @@ type Storage interface {
 	Save(session *models.Session) error
 	GetLast() (*models.Session, error)
 	GetAll() ([]models.Session, error)
-	GetByDateRange(start, end time.Time) ([]models.Session, error)
+	GetByDateRange(start, end time.Time, match ...RangeMatch) ([]models.Session, error)
 	GetByTask(task string) ([]models.Session, error)
 }
 
+// RangeMatch selects the sessions returned by GetByDateRange
+type RangeMatch int
+
+const (
+	// StartsInRange matches sessions that start within the range
+	StartsInRange RangeMatch = iota
+	// OverlapsRange matches sessions that are running for some time within
+	// the range, including unfinished sessions that started before its end.
+	// Sessions that merely touch the range, e.g. end at its start, are not
+	// matched.
+	OverlapsRange
+)
+
+// matches reports whether a session from start to end, zero for an
+// unfinished session, is matched by a range from rangeStart to rangeEnd
+func (m RangeMatch) matches(start, end, rangeStart, rangeEnd time.Time) bool {
+	if m == OverlapsRange {
+		return start.Before(rangeEnd) && (end.IsZero() || end.After(rangeStart))
+	}
+	return !start.Before(rangeStart) && !start.After(rangeEnd)
+}
+
 // FileStorage implements the Storage interface using a JSONL file.
 type FileStorage struct {
 	filePath string
@@ -93,7 +115,9 @@ func (s *FileStorage) GetLast() (*models.Session, error) {
 	return &sessions[len(sessions)-1], nil
 }
 
-// GetAll returns all sessions from the storage.
+// GetAll returns all sessions from the storage. Sessions are saved again
+// when they finish, so a record with the task and start time of an earlier
+// one replaces it.
 func (s *FileStorage) GetAll() ([]models.Session, error) {
 	file, err := os.Open(s.filePath)
 	if err != nil {
@@ -104,13 +128,24 @@ func (s *FileStorage) GetAll() ([]models.Session, error) {
 	}
 	defer file.Close()
 
+	type sessionKey struct {
+		task  string
+		start int64
+	}
 	var sessions []models.Session
+	index := make(map[sessionKey]int)
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		var session models.Session
 		if err := json.Unmarshal(scanner.Bytes(), &session); err != nil {
 			continue
 		}
+		key := sessionKey{session.Task, session.StartTime.UnixNano()}
+		if i, ok := index[key]; ok {
+			sessions[i] = session
+			continue
+		}
+		index[key] = len(sessions)
 		sessions = append(sessions, session)
 	}
 
@@ -122,16 +157,22 @@ func (s *FileStorage) GetAll() ([]models.Session, error) {
 }
 
 // GetByDateRange returns sessions within the specified date range (inclusive).
-func (s *FileStorage) GetByDateRange(start, end time.Time) ([]models.Session, error) {
+// By default only sessions that start within the range are returned, pass
+// OverlapsRange to also get those that started before it.
+func (s *FileStorage) GetByDateRange(start, end time.Time, match ...RangeMatch) ([]models.Session, error) {
 	sessions, err := s.GetAll()
 	if err != nil {
 		return nil, err
 	}
 
+	m := StartsInRange
+	if len(match) > 0 {
+		m = match[0]
+	}
+
 	var result []models.Session
 	for _, s := range sessions {
-		if (s.StartTime.After(start) || s.StartTime.Equal(start)) &&
-			(s.StartTime.Before(end) || s.StartTime.Equal(end)) {
+		if m.matches(s.StartTime, s.EndTime, start, end) {
 			result = append(result, s)
 		}
 	}
@@ -180,6 +180,28 @@ func TestFileStorage_SaveAndGetAll(t *testing.T) {
 	}
 }
 
+func TestFileStorage_GetAll_FinishedSession(t *testing.T) {
+	filePath, cleanup := setupTestFile(t)
+	defer cleanup()
+
+	fs, err := storage.NewFileStorage(filePath)
+	require.NoError(t, err)
+
+	start := time.Now().Add(-time.Hour)
+	session := &models.Session{Task: "task 1", StartTime: start}
+	require.NoError(t, fs.Save(session))
+	require.NoError(t, fs.Save(&models.Session{Task: "task 2", StartTime: start}))
+	session.EndTime = start.Add(30 * time.Minute)
+	require.NoError(t, fs.Save(session))
+
+	allSessions, err := fs.GetAll()
+	require.NoError(t, err)
+	require.Len(t, allSessions, 2, "The finished session replaces its running record")
+	assert.Equal(t, "task 1", allSessions[0].Task)
+	assert.WithinDuration(t, session.EndTime, allSessions[0].EndTime, time.Second)
+	assert.True(t, allSessions[1].EndTime.IsZero())
+}
+
 func TestFileStorage_GetLast_Empty(t *testing.T) {
 	filePath, cleanup := setupTestFile(t)
 	defer cleanup()
@@ -226,6 +248,42 @@ func TestFileStorage_GetLast(t *testing.T) {
 	assert.WithinDuration(t, session2.EndTime, last.EndTime, time.Second)
 }
 
+func TestFileStorage_GetByDateRange_Overlapping(t *testing.T) {
+	filePath, cleanup := setupTestFile(t)
+	defer cleanup()
+
+	fs, err := storage.NewFileStorage(filePath)
+	require.NoError(t, err)
+
+	day := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	testSessions := []models.Session{
+		{Task: "before", StartTime: day.Add(-3 * time.Hour), EndTime: day},
+		{Task: "across midnight", StartTime: day.Add(-2 * time.Hour), EndTime: day.Add(2 * time.Hour)},
+		{Task: "within", StartTime: day.Add(10 * time.Hour), EndTime: day.Add(11 * time.Hour)},
+		{Task: "next day", StartTime: day.Add(24 * time.Hour), EndTime: day.Add(25 * time.Hour)},
+		{Task: "running", StartTime: day.Add(23 * time.Hour)},
+	}
+	for i := range testSessions {
+		require.NoError(t, fs.Save(&testSessions[i]))
+	}
+
+	tasks := func(ssns []models.Session) []string {
+		var names []string
+		for _, s := range ssns {
+			names = append(names, s.Task)
+		}
+		return names
+	}
+
+	startsIn, err := fs.GetByDateRange(day, day.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"within", "next day", "running"}, tasks(startsIn))
+
+	overlapping, err := fs.GetByDateRange(day, day.Add(24*time.Hour), storage.OverlapsRange)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"across midnight", "within", "running"}, tasks(overlapping))
+}
+
 func TestFileStorage_GetByDateRange_Invalid(t *testing.T) {
 	filePath, cleanup := setupTestFile(t)
 	defer cleanup()
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+)
+
+// InterruptionStorage stores pomodoro interruptions in a JSONL file.
+type InterruptionStorage struct {
+	filePath string
+}
+
+// NewInterruptionStorage creates a new InterruptionStorage instance.
+func NewInterruptionStorage(filePath string) (*InterruptionStorage, error) {
+	if filePath == "" {
+		return nil, errors.New("file path cannot be empty")
+	}
+
+	if err := ensureFile(filePath); err != nil {
+		return nil, err
+	}
+
+	return &InterruptionStorage{
+		filePath: filePath,
+	}, nil
+}
+
+// Save appends an interruption to the storage file.
+func (s *InterruptionStorage) Save(interruption *models.Interruption) error {
+	if interruption == nil {
+		return errors.New("interruption cannot be nil")
+	}
+	if interruption.Time.IsZero() {
+		return errors.New("interruption time cannot be zero")
+	}
+
+	return appendJSONLine(s.filePath, interruption)
+}
+
+// Stat returns the file info of the storage file. Its modification time and
+// size change whenever an interruption is saved, so callers can tell when to
+// read the interruptions again.
+func (s *InterruptionStorage) Stat() (os.FileInfo, error) {
+	return os.Stat(s.filePath)
+}
+
+// GetAll returns all interruptions from the storage.
+func (s *InterruptionStorage) GetAll() ([]models.Interruption, error) {
+	return readJSONLines[models.Interruption](s.filePath)
+}
+
+// GetByDateRange returns interruptions that happened within the specified range (inclusive).
+func (s *InterruptionStorage) GetByDateRange(start, end time.Time) ([]models.Interruption, error) {
+	interruptions, err := s.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []models.Interruption
+	for _, i := range interruptions {
+		if !i.Time.Before(start) && !i.Time.After(end) {
+			result = append(result, i)
+		}
+	}
+
+	return result, nil
+}
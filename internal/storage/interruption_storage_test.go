@@ -0,0 +1,69 @@
+package storage_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/storage"
+)
+
+func TestNewInterruptionStorage_EmptyPath(t *testing.T) {
+	_, err := storage.NewInterruptionStorage("")
+	assert.Error(t, err)
+}
+
+func TestInterruptionStorage_Save_ErrorCases(t *testing.T) {
+	is, err := storage.NewInterruptionStorage(filepath.Join(t.TempDir(), "interruptions.jsonl"))
+	require.NoError(t, err)
+
+	assert.Error(t, is.Save(nil))
+	assert.Error(t, is.Save(&models.Interruption{Task: "task"}))
+}
+
+func TestInterruptionStorage_SaveAndGetByDateRange(t *testing.T) {
+	is, err := storage.NewInterruptionStorage(filepath.Join(t.TempDir(), "data", "interruptions.jsonl"))
+	require.NoError(t, err)
+
+	all, err := is.GetAll()
+	require.NoError(t, err)
+	assert.Empty(t, all)
+
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	saved := []models.Interruption{
+		{Task: "coding", Time: now, Reason: "slack"},
+		{Task: "coding", Time: now.Add(time.Hour), External: true, Void: true},
+		{Task: "writing", Time: now.Add(24 * time.Hour)},
+	}
+	for i := range saved {
+		require.NoError(t, is.Save(&saved[i]))
+	}
+
+	all, err = is.GetAll()
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "slack", all[0].Reason)
+	assert.True(t, all[1].Void)
+	assert.Equal(t, "external", all[1].Kind())
+
+	inRange, err := is.GetByDateRange(now, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, inRange, 2)
+}
+
+func TestInterruptionStorage_Stat(t *testing.T) {
+	is, err := storage.NewInterruptionStorage(filepath.Join(t.TempDir(), "interruptions.jsonl"))
+	require.NoError(t, err)
+
+	before, err := is.Stat()
+	require.NoError(t, err)
+	require.NoError(t, is.Save(&models.Interruption{Task: "coding", Time: time.Now()}))
+	after, err := is.Stat()
+	require.NoError(t, err)
+
+	assert.Greater(t, after.Size(), before.Size(), "Saving should change the file info")
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+)
+
+// PomodoroStorage stores finished pomodoro work intervals in a JSONL file.
+type PomodoroStorage struct {
+	filePath string
+}
+
+// NewPomodoroStorage creates a new PomodoroStorage instance.
+func NewPomodoroStorage(filePath string) (*PomodoroStorage, error) {
+	if filePath == "" {
+		return nil, errors.New("file path cannot be empty")
+	}
+
+	if err := ensureFile(filePath); err != nil {
+		return nil, err
+	}
+
+	return &PomodoroStorage{
+		filePath: filePath,
+	}, nil
+}
+
+// Save appends a pomodoro to the storage file.
+func (s *PomodoroStorage) Save(pomodoro *models.Pomodoro) error {
+	if pomodoro == nil {
+		return errors.New("pomodoro cannot be nil")
+	}
+	if pomodoro.StartTime.IsZero() {
+		return errors.New("pomodoro start time cannot be zero")
+	}
+	if pomodoro.EndTime.Before(pomodoro.StartTime) {
+		return errors.New("pomodoro end time cannot be before start time")
+	}
+
+	return appendJSONLine(s.filePath, pomodoro)
+}
+
+// GetAll returns all pomodoros from the storage.
+func (s *PomodoroStorage) GetAll() ([]models.Pomodoro, error) {
+	return readJSONLines[models.Pomodoro](s.filePath)
+}
+
+// GetByDateRange returns pomodoros that started within the specified range (inclusive).
+func (s *PomodoroStorage) GetByDateRange(start, end time.Time) ([]models.Pomodoro, error) {
+	pomodoros, err := s.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []models.Pomodoro
+	for _, p := range pomodoros {
+		if !p.StartTime.Before(start) && !p.StartTime.After(end) {
+			result = append(result, p)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,58 @@
+package storage_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/storage"
+)
+
+func TestNewPomodoroStorage_EmptyPath(t *testing.T) {
+	_, err := storage.NewPomodoroStorage("")
+	assert.Error(t, err)
+}
+
+func TestPomodoroStorage_Save_ErrorCases(t *testing.T) {
+	ps, err := storage.NewPomodoroStorage(filepath.Join(t.TempDir(), "pomodoros.jsonl"))
+	require.NoError(t, err)
+
+	now := time.Now()
+	assert.Error(t, ps.Save(nil))
+	assert.Error(t, ps.Save(&models.Pomodoro{Task: "task"}))
+	assert.Error(t, ps.Save(&models.Pomodoro{Task: "task", StartTime: now, EndTime: now.Add(-time.Minute)}))
+}
+
+func TestPomodoroStorage_SaveAndGetByDateRange(t *testing.T) {
+	ps, err := storage.NewPomodoroStorage(filepath.Join(t.TempDir(), "data", "pomodoros.jsonl"))
+	require.NoError(t, err)
+
+	all, err := ps.GetAll()
+	require.NoError(t, err)
+	assert.Empty(t, all)
+
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	saved := []models.Pomodoro{
+		{Task: "coding", StartTime: now, EndTime: now.Add(25 * time.Minute), Planned: 25 * time.Minute, Completed: true},
+		{Task: "coding", StartTime: now.Add(30 * time.Minute), EndTime: now.Add(40 * time.Minute), Planned: 25 * time.Minute},
+		{Task: "writing", StartTime: now.Add(24 * time.Hour), EndTime: now.Add(25 * time.Hour)},
+	}
+	for i := range saved {
+		require.NoError(t, ps.Save(&saved[i]))
+	}
+
+	all, err = ps.GetAll()
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.True(t, all[0].Completed)
+	assert.Equal(t, 25*time.Minute, all[0].Duration())
+	assert.False(t, all[1].Completed)
+
+	inRange, err := ps.GetByDateRange(now, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, inRange, 2)
+}
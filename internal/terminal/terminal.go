@@ -0,0 +1,20 @@
+// Package terminal switches the controlling terminal into a mode where single
+// key presses can be read without waiting for Enter
+package terminal
+
+import "errors"
+
+// ErrNotTerminal is returned when the file descriptor is not a terminal, e.g.
+// when input is piped in
+var ErrNotTerminal = errors.New("not a terminal")
+
+// RestoreFunc puts the terminal back into the mode it was in before
+type RestoreFunc func() error
+
+// MakeCbreak disables line buffering and echo on the terminal behind fd, so
+// every key press is delivered as soon as it is typed. Signal keys such as
+// Ctrl+C keep working. The returned function restores the previous mode and
+// must be called before the program exits.
+func MakeCbreak(fd int) (RestoreFunc, error) {
+	return makeCbreak(fd)
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package terminal
+
+// makeCbreak is not supported on this platform, so callers fall back to
+// line-buffered input
+func makeCbreak(int) (RestoreFunc, error) {
+	return nil, ErrNotTerminal
+}
@@ -0,0 +1,35 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package terminal
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+func makeCbreak(fd int) (RestoreFunc, error) {
+	old, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		if errors.Is(err, unix.ENOTTY) || errors.Is(err, unix.ENODEV) {
+			return nil, ErrNotTerminal
+		}
+		return nil, fmt.Errorf("failed to read terminal mode: %w", err)
+	}
+
+	cbreak := *old
+	cbreak.Lflag &^= unix.ICANON | unix.ECHO
+	cbreak.Cc[unix.VMIN] = 1
+	cbreak.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &cbreak); err != nil {
+		return nil, fmt.Errorf("failed to set terminal mode: %w", err)
+	}
+
+	return func() error {
+		if err := unix.IoctlSetTermios(fd, ioctlSetTermios, old); err != nil {
+			return fmt.Errorf("failed to restore terminal mode: %w", err)
+		}
+		return nil
+	}, nil
+}
@@ -1,51 +1,88 @@
 package analytics
 
 import (
+	"slices"
 	"sort"
 	"time"
 
 	"github.com/AndriyBarskyi/gotrack/internal/models"
 )
 
-const (
-	hoursInDay = 24
-	
-	maxHoursForPerfectScore = 100.0
-	maxDaysForPerfectConsistency = 30.0
-	maxStreakForPerfectScore = 100.0
-	
-	hoursWeight = 0.4
-	consistencyWeight = 0.4
-	streakWeight = 0.2
-	
-	maxProductivityScore = 100.0
-)
-
 // CalculateTotalDuration returns the total duration of all sessions.
+// The running session counts with the time elapsed so far.
 func CalculateTotalDuration(ssns []models.Session, task string) time.Duration {
 	var totalDuration time.Duration
+	now := time.Now()
 	for _, ssn := range ssns {
 		if task == "" || ssn.Task == task {
-			totalDuration += ssn.EndTime.Sub(ssn.StartTime)
+			totalDuration += elapsed(ssn, now)
 		}
 	}
 	return totalDuration
 }
 
-// CalculateTodayDuration returns the total duration of all sessions that started today.
-func CalculateTodayDuration(ssns []models.Session, task string) time.Duration {
-	var todayDuration time.Duration
-	today := time.Now().Format("2006-01-02")
+// CalculateTodayDuration returns the time spent on sessions today. Sessions
+// that cross the start of the day only count with their part after it.
+func CalculateTodayDuration(ssns []models.Session, task string, days Days) time.Duration {
+	now := time.Now()
+	return durationBetween(ssns, task, days.Start(now), days.Next(now), now)
+}
+
+// Finished returns the sessions that have ended, leaving out the running one
+func Finished(ssns []models.Session) []models.Session {
+	finished := make([]models.Session, 0, len(ssns))
 	for _, ssn := range ssns {
-		if ssn.StartTime.Format("2006-01-02") == today && (task == "" || ssn.Task == task) {
-			todayDuration += ssn.EndTime.Sub(ssn.StartTime)
+		if !ssn.IsActive() {
+			finished = append(finished, ssn)
 		}
 	}
-	return todayDuration
+	return finished
+}
+
+// sessionEnd returns when a session ended, or now while it is running.
+// Sessions never count beyond now.
+func sessionEnd(ssn models.Session, now time.Time) time.Time {
+	if ssn.EndTime.IsZero() || ssn.EndTime.After(now) {
+		return now
+	}
+	return ssn.EndTime
+}
+
+// elapsed returns the duration of a session, up to now for the running one
+func elapsed(ssn models.Session, now time.Time) time.Duration {
+	return durationWithin(ssn, ssn.StartTime, now, now)
+}
+
+// durationWithin returns the part of a session that falls between start and
+// end, counting the running session up to now
+func durationWithin(ssn models.Session, start, end, now time.Time) time.Duration {
+	from, to := ssn.StartTime, sessionEnd(ssn, now)
+	if start.After(from) {
+		from = start
+	}
+	if end.Before(to) {
+		to = end
+	}
+	if !to.After(from) {
+		return 0
+	}
+	return to.Sub(from)
+}
+
+// durationBetween returns the time spent on sessions of the task, or of all
+// tasks when it is empty, between start and end
+func durationBetween(ssns []models.Session, task string, start, end, now time.Time) time.Duration {
+	var total time.Duration
+	for _, ssn := range ssns {
+		if task == "" || ssn.Task == task {
+			total += durationWithin(ssn, start, end, now)
+		}
+	}
+	return total
 }
 
 // CalculateConsecutiveDays returns the number of recent consecutive days of tracking.
-func CalculateConsecutiveDays(ssns []models.Session) int {
+func CalculateConsecutiveDays(ssns []models.Session, days Days) int {
 	if len(ssns) == 0 {
 		return 0
 	}
@@ -57,72 +94,52 @@ func CalculateConsecutiveDays(ssns []models.Session) int {
 	})
 
 	consecutiveDays := 1
-	currentDate := sorted[0].StartTime.Truncate(hoursInDay * time.Hour)
+	currentDay := days.Key(sorted[0].StartTime)
 
 	for i := 1; i < len(sorted); i++ {
-		sessionDate := sorted[i].StartTime.Truncate(hoursInDay * time.Hour)
-		if currentDate.Sub(sessionDate) == hoursInDay*time.Hour {
-			consecutiveDays++
-			currentDate = sessionDate
-		} else if currentDate.Sub(sessionDate) > hoursInDay*time.Hour {
+		sessionDay := days.Key(sorted[i].StartTime)
+		if sessionDay == currentDay {
+			continue
+		}
+		if sessionDay != prevKey(currentDay) {
 			break
 		}
+		consecutiveDays++
+		currentDay = sessionDay
 	}
 
 	return consecutiveDays
 }
 
-// CalculateWeeklyDuration returns the total duration for the current week
-func CalculateWeeklyDuration(ssns []models.Session, task string) time.Duration {
-	var weeklyDuration time.Duration
+// CalculateWeeklyDuration returns the time spent on sessions this week. Sessions
+// that cross the start of the week only count with their part after it.
+func CalculateWeeklyDuration(ssns []models.Session, task string, days Days) time.Duration {
 	now := time.Now()
-	startOfWeek := now.AddDate(0, 0, -int(now.Weekday()))
-	startOfWeek = time.Date(startOfWeek.Year(), startOfWeek.Month(), startOfWeek.Day(), 0, 0, 0, 0, startOfWeek.Location())
-	
-	for _, ssn := range ssns {
-		if ssn.StartTime.After(startOfWeek) && (task == "" || ssn.Task == task) {
-			weeklyDuration += ssn.EndTime.Sub(ssn.StartTime)
-		}
-	}
-	return weeklyDuration
+	return durationBetween(ssns, task, days.WeekStart(now), days.Next(now), now)
 }
 
-// CalculateMonthlyDuration returns the total duration for the current month
-func CalculateMonthlyDuration(ssns []models.Session, task string) time.Duration {
-	var monthlyDuration time.Duration
+// CalculateMonthlyDuration returns the time spent on sessions this month. Sessions
+// that cross the start of the month only count with their part after it.
+func CalculateMonthlyDuration(ssns []models.Session, task string, days Days) time.Duration {
 	now := time.Now()
-	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
-	
-	for _, ssn := range ssns {
-		if ssn.StartTime.After(startOfMonth) && (task == "" || ssn.Task == task) {
-			monthlyDuration += ssn.EndTime.Sub(ssn.StartTime)
-		}
-	}
-	return monthlyDuration
+	return durationBetween(ssns, task, days.MonthStart(now), days.Next(now), now)
 }
 
-// CalculateYearlyDuration returns the total duration for the current year
-func CalculateYearlyDuration(ssns []models.Session, task string) time.Duration {
-	var yearlyDuration time.Duration
+// CalculateYearlyDuration returns the time spent on sessions this year. Sessions
+// that cross the start of the year only count with their part after it.
+func CalculateYearlyDuration(ssns []models.Session, task string, days Days) time.Duration {
 	now := time.Now()
-	startOfYear := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
-	
-	for _, ssn := range ssns {
-		if ssn.StartTime.After(startOfYear) && (task == "" || ssn.Task == task) {
-			yearlyDuration += ssn.EndTime.Sub(ssn.StartTime)
-		}
-	}
-	return yearlyDuration
+	return durationBetween(ssns, task, days.YearStart(now), days.Next(now), now)
 }
 
-// GetTopTasks returns the most worked on tasks with their durations
+// GetTopTasks returns the most worked on tasks with their durations.
+// The running session counts with the time elapsed so far.
 func GetTopTasks(ssns []models.Session, limit int) []TaskStats {
 	taskDurations := make(map[string]time.Duration)
+	now := time.Now()
 	
 	for _, ssn := range ssns {
-		if !ssn.EndTime.IsZero() {
-			taskDurations[ssn.Task] += ssn.EndTime.Sub(ssn.StartTime)
-		}
+		taskDurations[ssn.Task] += elapsed(ssn, now)
 	}
 	
 	var stats []TaskStats
@@ -151,71 +168,42 @@ type TaskStats struct {
 }
 
 // CalculateLongestStreak returns the longest consecutive days streak in history
-func CalculateLongestStreak(ssns []models.Session) int {
+func CalculateLongestStreak(ssns []models.Session, days Days) int {
 	if len(ssns) == 0 {
 		return 0
 	}
 	
 	daySet := make(map[string]bool)
 	for _, ssn := range ssns {
-		day := ssn.StartTime.Format("2006-01-02")
-		daySet[day] = true
-	}
-	
-	var days []time.Time
-	for dayStr := range daySet {
-		day, _ := time.Parse("2006-01-02", dayStr)
-		days = append(days, day)
+		daySet[days.Key(ssn.StartTime)] = true
 	}
 	
-	sort.Slice(days, func(i, j int) bool {
-		return days[i].Before(days[j])
-	})
-	
-	if len(days) == 0 {
-		return 0
-	}
-	
-	maxStreak := 1
-	currentStreak := 1
-	
-	for i := 1; i < len(days); i++ {
-		if days[i].Sub(days[i-1]) == hoursInDay*time.Hour {
-			currentStreak++
-			if currentStreak > maxStreak {
-				maxStreak = currentStreak
-			}
-		} else {
-			currentStreak = 1
-		}
+	var keys []string
+	for day := range daySet {
+		keys = append(keys, day)
 	}
 	
+	_, maxStreak := longestStreak(keys)
 	return maxStreak
 }
 
-// GetProductivityScore calculates a productivity score based on consistency and volume
-func GetProductivityScore(ssns []models.Session) float64 {
-	if len(ssns) == 0 {
-		return 0.0
-	}
-	
-	totalDuration := CalculateTotalDuration(ssns, "")
-	consecutiveDays := CalculateConsecutiveDays(ssns)
-	longestStreak := CalculateLongestStreak(ssns)
+// longestStreak returns the first day and the length of the longest run of
+// consecutive days among day keys, the most recent one on ties
+func longestStreak(keys []string) (first string, length int) {
+	keys = slices.Clone(keys)
+	sort.Strings(keys)
 	
-	hoursScore := float64(totalDuration.Hours()) / maxHoursForPerfectScore
-	consistencyScore := float64(consecutiveDays) / maxDaysForPerfectConsistency
-	streakScore := float64(longestStreak) / maxStreakForPerfectScore
-	
-	if hoursScore > 1.0 {
-		hoursScore = 1.0
-	}
-	if consistencyScore > 1.0 {
-		consistencyScore = 1.0
-	}
-	if streakScore > 1.0 {
-		streakScore = 1.0
+	start, currentStreak := 0, 0
+	for i := range keys {
+		if i > 0 && keys[i] == nextKey(keys[i-1]) {
+			currentStreak++
+		} else {
+			start, currentStreak = i, 1
+		}
+		if currentStreak >= length {
+			first, length = keys[start], currentStreak
+		}
 	}
 	
-	return (hoursScore*hoursWeight + consistencyScore*consistencyWeight + streakScore*streakWeight) * maxProductivityScore
+	return first, length
 }
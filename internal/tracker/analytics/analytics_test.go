@@ -7,6 +7,7 @@ import (
 	"github.com/AndriyBarskyi/gotrack/internal/models"
 	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCalculateTotalDuration(t *testing.T) {
@@ -87,6 +88,18 @@ func TestCalculateTodayDuration(t *testing.T) {
 			task:     "",
 			expected: time.Hour,
 		},
+		{
+			name: "session across the start of the day",
+			sessions: []models.Session{
+				{
+					Task:      "test",
+					StartTime: today.Add(-2 * time.Hour),
+					EndTime:   today.Add(30 * time.Minute),
+				},
+			},
+			task:     "",
+			expected: 30 * time.Minute,
+		},
 		{
 			name: "session from yesterday",
 			sessions: []models.Session{
@@ -103,7 +116,7 @@ func TestCalculateTodayDuration(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := analytics.CalculateTodayDuration(tt.sessions, tt.task)
+			result := analytics.CalculateTodayDuration(tt.sessions, tt.task, analytics.LocalDays())
 			if tt.expected == 0 {
 				assert.Equal(t, tt.expected, result)
 			} else {
@@ -171,8 +184,54 @@ func TestCalculateConsecutiveDays(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := analytics.CalculateConsecutiveDays(tt.sessions)
+			result := analytics.CalculateConsecutiveDays(tt.sessions, analytics.Days{Location: time.UTC})
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
+
+func TestCalculatePeriodDurations_SplitSessions(t *testing.T) {
+	days := analytics.LocalDays()
+	now := time.Now()
+	weekStart := days.WeekStart(now)
+	monthStart := days.MonthStart(now)
+	yearStart := days.YearStart(now)
+
+	acrossWeek := []models.Session{{Task: "test", StartTime: weekStart.Add(-time.Hour), EndTime: weekStart.Add(time.Minute)}}
+	acrossMonth := []models.Session{{Task: "test", StartTime: monthStart.Add(-time.Hour), EndTime: monthStart.Add(time.Minute)}}
+	acrossYear := []models.Session{{Task: "test", StartTime: yearStart.Add(-time.Hour), EndTime: yearStart.Add(time.Minute)}}
+
+	assert.Equal(t, time.Minute, analytics.CalculateWeeklyDuration(acrossWeek, "", days))
+	assert.Equal(t, time.Minute, analytics.CalculateMonthlyDuration(acrossMonth, "", days))
+	assert.Equal(t, time.Minute, analytics.CalculateYearlyDuration(acrossYear, "", days))
+}
+
+func TestRunningSession(t *testing.T) {
+	days := analytics.LocalDays()
+	now := time.Now()
+	start := now.Add(-10 * time.Minute)
+	if dayStart := days.Start(now); start.Before(dayStart) {
+		start = dayStart
+	}
+	running := time.Since(start)
+
+	sessions := []models.Session{
+		{Task: "done", StartTime: start.Add(-time.Hour), EndTime: start.Add(-30 * time.Minute)},
+		{Task: "running", StartTime: start},
+	}
+
+	assert.InDelta(t, (30*time.Minute + running).Seconds(), analytics.CalculateTotalDuration(sessions, "").Seconds(), 1)
+	assert.InDelta(t, running.Seconds(), analytics.CalculateTodayDuration(sessions, "running", days).Seconds(), 1)
+	assert.InDelta(t, running.Seconds(), analytics.CalculateWeeklyDuration(sessions, "running", days).Seconds(), 1)
+
+	top := analytics.GetTopTasks(sessions, 0)
+	require.Len(t, top, 2)
+	assert.Contains(t, []string{"done", "running"}, top[0].Task)
+	for _, task := range top {
+		assert.Positive(t, task.Duration)
+	}
+
+	finished := analytics.Finished(sessions)
+	assert.Equal(t, sessions[:1], finished)
+	assert.Equal(t, 30*time.Minute, analytics.CalculateTotalDuration(finished, ""))
+}
@@ -0,0 +1,114 @@
+package analytics
+
+import (
+	"slices"
+	"time"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+)
+
+// BudgetThresholds are the shares of a budget whose crossing is reported
+var BudgetThresholds = []float64{0.8, 1}
+
+// Budget is the time available for a task, a tag or a project per period
+type Budget struct {
+	Name string
+	// Task, Tag and Project select the sessions counted against the budget,
+	// empty values match every session
+	Task    string
+	Tag     string
+	Project string
+	Limit   time.Duration
+	// Period is "day", "week", "month" or "year"
+	Period string
+}
+
+// Matches reports whether a session counts against the budget
+func (b Budget) Matches(ssn models.Session) bool {
+	return (b.Task == "" || ssn.Task == b.Task) &&
+		(b.Project == "" || ssn.Project == b.Project) &&
+		(b.Tag == "" || slices.Contains(ssn.Tags, b.Tag))
+}
+
+// BudgetStatus is the use of a budget in its current period
+type BudgetStatus struct {
+	Budget
+	// Current runs from the start of the current period to its end
+	Current Period
+	Used    time.Duration
+	// Projected is the time used by the end of the period if work goes on at
+	// the rate of the period so far
+	Projected time.Duration
+}
+
+// Remaining returns the time left in the budget, zero once it is used up
+func (s BudgetStatus) Remaining() time.Duration {
+	return max(s.Limit-s.Used, 0)
+}
+
+// Share returns the part of the budget that is used, above 1 when over it
+func (s BudgetStatus) Share() float64 {
+	if s.Limit <= 0 {
+		return 0
+	}
+	return float64(s.Used) / float64(s.Limit)
+}
+
+// UsedWithout returns the time the budget used without a session
+func (s BudgetStatus) UsedWithout(ssn models.Session, now time.Time) time.Duration {
+	if !s.Matches(ssn) {
+		return s.Used
+	}
+	return s.Used - durationWithin(ssn, s.Current.From, s.Current.To, now)
+}
+
+// Crossed returns the highest threshold of BudgetThresholds the budget
+// reached since it had used before. It reports false when no threshold was
+// crossed.
+func (s BudgetStatus) Crossed(before time.Duration) (float64, bool) {
+	if s.Limit <= 0 {
+		return 0, false
+	}
+	prev := float64(before) / float64(s.Limit)
+	for i := len(BudgetThresholds) - 1; i >= 0; i-- {
+		if t := BudgetThresholds[i]; prev < t && s.Share() >= t {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// PeriodOf returns the day, week, month or year t falls in
+func (d Days) PeriodOf(period string, t time.Time) Period {
+	switch period {
+	case "day":
+		return Period{d.Start(t), d.Next(t)}
+	case "week":
+		start := d.WeekStart(t)
+		return Period{start, d.at(start.Year(), start.Month(), start.Day()+7)}
+	case "year":
+		start := d.YearStart(t)
+		return Period{start, d.at(start.Year()+1, time.January, 1)}
+	default:
+		start := d.MonthStart(t)
+		return Period{start, d.at(start.Year(), start.Month()+1, 1)}
+	}
+}
+
+// CheckBudget returns the use of a budget in the period now falls in. The
+// running session counts up to now.
+func CheckBudget(ssns []models.Session, b Budget, days Days, now time.Time) BudgetStatus {
+	s := BudgetStatus{Budget: b, Current: days.PeriodOf(b.Period, now)}
+	for _, ssn := range ssns {
+		if b.Matches(ssn) {
+			s.Used += durationWithin(ssn, s.Current.From, s.Current.To, now)
+		}
+	}
+
+	s.Projected = s.Used
+	if elapsed := now.Sub(s.Current.From); elapsed > 0 {
+		rate := float64(s.Used) / float64(elapsed)
+		s.Projected = time.Duration(rate * float64(s.Current.To.Sub(s.Current.From)))
+	}
+	return s
+}
@@ -0,0 +1,70 @@
+package analytics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
+)
+
+func TestCheckBudget(t *testing.T) {
+	days := analytics.Days{Location: time.UTC}
+	now := time.Date(2024, 6, 11, 0, 0, 0, 0, time.UTC) // a third of June has passed
+	ssns := []models.Session{
+		{Task: "coding", Project: "client-a", StartTime: time.Date(2024, 5, 31, 22, 0, 0, 0, time.UTC), EndTime: time.Date(2024, 6, 1, 2, 0, 0, 0, time.UTC)},
+		{Task: "review", Project: "client-a", StartTime: time.Date(2024, 6, 5, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2024, 6, 5, 15, 0, 0, 0, time.UTC)},
+		{Task: "email", Project: "client-b", StartTime: time.Date(2024, 6, 6, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2024, 6, 6, 10, 0, 0, 0, time.UTC)},
+		{Task: "coding", Project: "client-a", StartTime: now.Add(-2 * time.Hour)},
+	}
+	budget := analytics.Budget{Name: "client-a", Project: "client-a", Limit: 12 * time.Hour, Period: "month"}
+
+	s := analytics.CheckBudget(ssns, budget, days, now)
+	assert.Equal(t, analytics.Period{From: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)}, s.Current)
+	assert.Equal(t, 10*time.Hour, s.Used, "Only the part in June counts, the running session up to now")
+	assert.Equal(t, 2*time.Hour, s.Remaining())
+	assert.InDelta(t, 10.0/12, s.Share(), 1e-9)
+	assert.Equal(t, 30*time.Hour, s.Projected)
+
+	threshold, crossed := s.Crossed(8 * time.Hour)
+	assert.True(t, crossed)
+	assert.Equal(t, 0.8, threshold)
+	_, crossed = s.Crossed(9*time.Hour + 36*time.Minute)
+	assert.False(t, crossed, "80% was already used")
+
+	s.Used = 13 * time.Hour
+	assert.Zero(t, s.Remaining())
+	threshold, _ = s.Crossed(0)
+	assert.Equal(t, 1.0, threshold, "The highest threshold crossed is reported")
+}
+
+func TestBudget_Matches(t *testing.T) {
+	ssn := models.Session{Task: "reading", Project: "gotrack", Tags: []string{"learning"}}
+
+	assert.True(t, analytics.Budget{Tag: "learning"}.Matches(ssn))
+	assert.True(t, analytics.Budget{Project: "gotrack"}.Matches(ssn))
+	assert.False(t, analytics.Budget{Task: "coding"}.Matches(ssn))
+}
+
+func TestBudgetStatus_UsedWithout(t *testing.T) {
+	days := analytics.Days{Location: time.UTC}
+	now := time.Date(2024, 6, 5, 12, 0, 0, 0, time.UTC)
+	ssn := models.Session{Task: "coding", StartTime: now.Add(-3 * time.Hour), EndTime: now.Add(-time.Hour)}
+	s := analytics.CheckBudget([]models.Session{ssn}, analytics.Budget{Task: "coding", Limit: time.Hour, Period: "day"}, days, now)
+
+	assert.Equal(t, 2*time.Hour, s.Used)
+	assert.Zero(t, s.UsedWithout(ssn, now))
+}
+
+func TestDays_PeriodOf(t *testing.T) {
+	days := analytics.Days{Location: time.UTC}
+	now := time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC) // a Tuesday
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	assert.Equal(t, analytics.Period{From: date(2024, 12, 31), To: date(2025, 1, 1)}, days.PeriodOf("day", now))
+	assert.Equal(t, analytics.Period{From: date(2024, 12, 29), To: date(2025, 1, 5)}, days.PeriodOf("week", now))
+	assert.Equal(t, analytics.Period{From: date(2024, 12, 1), To: date(2025, 1, 1)}, days.PeriodOf("month", now))
+	assert.Equal(t, analytics.Period{From: date(2024, 1, 1), To: date(2025, 1, 1)}, days.PeriodOf("year", now))
+}
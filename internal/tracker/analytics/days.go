@@ -0,0 +1,127 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+)
+
+// Days splits time into the calendar days of a timezone. A day runs from
+// StartHour on its date to StartHour on the next date, so with a StartHour
+// of 4 a session at 1am still counts towards the previous day.
+type Days struct {
+	// Location is the timezone of the days, nil means local time
+	Location *time.Location
+	// StartHour is the hour of the day, between 0 and 23, at which days begin
+	StartHour int
+}
+
+// LocalDays returns days in local time that begin at midnight
+func LocalDays() Days {
+	return Days{Location: time.Local}
+}
+
+func (d Days) location() *time.Location {
+	if d.Location == nil {
+		return time.Local
+	}
+	return d.Location
+}
+
+// at returns the start of the day with the given date. Dates out of range
+// are normalized like time.Date does, so at(y, m, day+1) is the next day.
+func (d Days) at(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, d.StartHour, 0, 0, 0, d.location())
+}
+
+// Start returns the start of the day t falls in
+func (d Days) Start(t time.Time) time.Time {
+	t = t.In(d.location())
+	start := d.at(t.Year(), t.Month(), t.Day())
+	if start.After(t) {
+		start = d.at(t.Year(), t.Month(), t.Day()-1)
+	}
+	return start
+}
+
+// Next returns the start of the day after the one t falls in. Days are not
+// always 24 hours long: they are one hour shorter or longer when the clocks
+// change.
+func (d Days) Next(t time.Time) time.Time {
+	start := d.Start(t)
+	return d.at(start.Year(), start.Month(), start.Day()+1)
+}
+
+// Key returns the date of the day t falls in, e.g. "2024-03-31"
+func (d Days) Key(t time.Time) string {
+	return d.Start(t).Format(dayLayout)
+}
+
+// Same reports whether a and b fall in the same day
+func (d Days) Same(a, b time.Time) bool {
+	return d.Start(a).Equal(d.Start(b))
+}
+
+// WeekStart returns the start of the week t falls in, weeks begin on Sunday
+func (d Days) WeekStart(t time.Time) time.Time {
+	start := d.Start(t)
+	return d.at(start.Year(), start.Month(), start.Day()-int(start.Weekday()))
+}
+
+// MonthStart returns the start of the first day of the month t falls in
+func (d Days) MonthStart(t time.Time) time.Time {
+	start := d.Start(t)
+	return d.at(start.Year(), start.Month(), 1)
+}
+
+// YearStart returns the start of the first day of the year t falls in
+func (d Days) YearStart(t time.Time) time.Time {
+	start := d.Start(t)
+	return d.at(start.Year(), time.January, 1)
+}
+
+// Year returns the start of the first day of a year and of the year after it
+func (d Days) Year(year int) (start, end time.Time) {
+	return d.at(year, time.January, 1), d.at(year+1, time.January, 1)
+}
+
+// Split cuts a session at the day boundaries it crosses and returns one part
+// per day, first day first. Unfinished sessions are returned as they are.
+func (d Days) Split(ssn models.Session) []models.Session {
+	if ssn.EndTime.IsZero() || !ssn.EndTime.After(ssn.StartTime) {
+		return []models.Session{ssn}
+	}
+
+	var parts []models.Session
+	for start := ssn.StartTime; start.Before(ssn.EndTime); {
+		end := d.Next(start)
+		if end.After(ssn.EndTime) {
+			end = ssn.EndTime
+		}
+		part := ssn
+		part.StartTime, part.EndTime = start, end
+		parts = append(parts, part)
+		start = end
+	}
+	return parts
+}
+
+// nextKey returns the key of the day after the day with the given key.
+// Keys are plain dates, so the arithmetic is done in UTC where every day is
+// 24 hours long.
+func nextKey(key string) string {
+	day, err := time.Parse(dayLayout, key)
+	if err != nil {
+		return ""
+	}
+	return day.AddDate(0, 0, 1).Format(dayLayout)
+}
+
+// prevKey returns the key of the day before the day with the given key
+func prevKey(key string) string {
+	day, err := time.Parse(dayLayout, key)
+	if err != nil {
+		return ""
+	}
+	return day.AddDate(0, 0, -1).Format(dayLayout)
+}
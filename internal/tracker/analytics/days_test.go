@@ -0,0 +1,221 @@
+package analytics_test
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
+)
+
+func loadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	require.NoError(t, err)
+	return loc
+}
+
+func TestDays_Start(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name      string
+		days      analytics.Days
+		time      time.Time
+		key       string
+		dayLength time.Duration
+	}{
+		{
+			name:      "midnight",
+			days:      analytics.Days{Location: ny},
+			time:      time.Date(2024, 6, 1, 0, 0, 0, 0, ny),
+			key:       "2024-06-01",
+			dayLength: 24 * time.Hour,
+		},
+		{
+			name:      "other timezone",
+			days:      analytics.Days{Location: ny},
+			time:      time.Date(2024, 6, 1, 2, 0, 0, 0, time.UTC),
+			key:       "2024-05-31",
+			dayLength: 24 * time.Hour,
+		},
+		{
+			name:      "before the start hour",
+			days:      analytics.Days{Location: ny, StartHour: 4},
+			time:      time.Date(2024, 6, 1, 3, 59, 0, 0, ny),
+			key:       "2024-05-31",
+			dayLength: 24 * time.Hour,
+		},
+		{
+			name:      "at the start hour",
+			days:      analytics.Days{Location: ny, StartHour: 4},
+			time:      time.Date(2024, 6, 1, 4, 0, 0, 0, ny),
+			key:       "2024-06-01",
+			dayLength: 24 * time.Hour,
+		},
+		{
+			name:      "clocks go forward",
+			days:      analytics.Days{Location: ny},
+			time:      time.Date(2024, 3, 10, 12, 0, 0, 0, ny),
+			key:       "2024-03-10",
+			dayLength: 23 * time.Hour,
+		},
+		{
+			name:      "clocks go back",
+			days:      analytics.Days{Location: ny},
+			time:      time.Date(2024, 11, 3, 23, 30, 0, 0, ny),
+			key:       "2024-11-03",
+			dayLength: 25 * time.Hour,
+		},
+		{
+			name:      "clocks go back with a start hour",
+			days:      analytics.Days{Location: ny, StartHour: 4},
+			time:      time.Date(2024, 11, 3, 3, 30, 0, 0, ny),
+			key:       "2024-11-02",
+			dayLength: 25 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := tt.days.Start(tt.time)
+			next := tt.days.Next(tt.time)
+
+			assert.Equal(t, tt.key, tt.days.Key(tt.time))
+			assert.False(t, start.After(tt.time))
+			assert.True(t, next.After(tt.time))
+			assert.Equal(t, tt.days.StartHour, start.In(ny).Hour())
+			assert.Equal(t, tt.dayLength, next.Sub(start))
+		})
+	}
+}
+
+func TestDays_PeriodStarts(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+	days := analytics.Days{Location: ny, StartHour: 4}
+
+	// Sunday 2024-03-10 02:00 is when the clocks go forward, and 3am on
+	// Monday still belongs to Sunday
+	now := time.Date(2024, 3, 11, 3, 0, 0, 0, ny)
+
+	assert.Equal(t, time.Date(2024, 3, 10, 4, 0, 0, 0, ny), days.WeekStart(now))
+	assert.Equal(t, time.Date(2024, 3, 1, 4, 0, 0, 0, ny), days.MonthStart(now))
+	assert.Equal(t, time.Date(2024, 1, 1, 4, 0, 0, 0, ny), days.YearStart(now))
+	assert.Equal(t, time.Date(2023, 1, 1, 4, 0, 0, 0, ny), days.YearStart(time.Date(2024, 1, 1, 1, 0, 0, 0, ny)))
+}
+
+func TestStreaks_AcrossTimezonesAndDST(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+	tokyo := loadLocation(t, "Asia/Tokyo")
+
+	// Sessions late in the evening around the clocks going forward and back
+	// are on consecutive local days, even though some are on the same UTC day
+	evenings := func(dates ...time.Time) []models.Session {
+		ssns := make([]models.Session, len(dates))
+		for i, d := range dates {
+			ssns[i] = models.Session{Task: "test", StartTime: d, EndTime: d.Add(30 * time.Minute)}
+		}
+		return ssns
+	}
+	spring := evenings(
+		time.Date(2024, 3, 9, 21, 30, 0, 0, ny),
+		time.Date(2024, 3, 10, 21, 30, 0, 0, ny),
+		time.Date(2024, 3, 11, 21, 30, 0, 0, ny),
+	)
+	autumn := evenings(
+		time.Date(2024, 11, 2, 23, 30, 0, 0, ny),
+		time.Date(2024, 11, 3, 23, 30, 0, 0, ny),
+		time.Date(2024, 11, 4, 0, 30, 0, 0, ny),
+	)
+	nyDays := analytics.Days{Location: ny}
+
+	assert.Equal(t, 3, analytics.CalculateConsecutiveDays(spring, nyDays))
+	assert.Equal(t, 3, analytics.CalculateLongestStreak(spring, nyDays))
+	assert.Equal(t, 3, analytics.CalculateConsecutiveDays(autumn, nyDays))
+	assert.Equal(t, 3, analytics.CalculateLongestStreak(autumn, nyDays))
+
+	// Morning sessions in Tokyo happen on the previous UTC day
+	mornings := evenings(
+		time.Date(2024, 6, 1, 8, 0, 0, 0, tokyo),
+		time.Date(2024, 6, 2, 8, 0, 0, 0, tokyo),
+		time.Date(2024, 6, 2, 10, 0, 0, 0, tokyo),
+	)
+	assert.Equal(t, 2, analytics.CalculateConsecutiveDays(mornings, analytics.Days{Location: tokyo}))
+	assert.Equal(t, 2, analytics.CalculateLongestStreak(mornings, analytics.Days{Location: tokyo}))
+
+	// With days starting at 4am, working past midnight does not add a day
+	nights := evenings(
+		time.Date(2024, 6, 1, 22, 0, 0, 0, tokyo),
+		time.Date(2024, 6, 2, 1, 0, 0, 0, tokyo),
+		time.Date(2024, 6, 3, 22, 0, 0, 0, tokyo),
+	)
+	assert.Equal(t, 3, analytics.CalculateLongestStreak(nights, analytics.Days{Location: tokyo}))
+	assert.Equal(t, 1, analytics.CalculateLongestStreak(nights, analytics.Days{Location: tokyo, StartHour: 4}))
+}
+
+func TestDays_Split(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name    string
+		days    analytics.Days
+		session models.Session
+		parts   []time.Duration
+	}{
+		{
+			name:    "within a day",
+			days:    analytics.Days{Location: ny},
+			session: models.Session{StartTime: time.Date(2024, 6, 1, 9, 0, 0, 0, ny), EndTime: time.Date(2024, 6, 1, 17, 0, 0, 0, ny)},
+			parts:   []time.Duration{8 * time.Hour},
+		},
+		{
+			name:    "across midnight",
+			days:    analytics.Days{Location: ny},
+			session: models.Session{StartTime: time.Date(2024, 6, 1, 22, 0, 0, 0, ny), EndTime: time.Date(2024, 6, 2, 2, 0, 0, 0, ny)},
+			parts:   []time.Duration{2 * time.Hour, 2 * time.Hour},
+		},
+		{
+			name:    "across midnight before the start hour",
+			days:    analytics.Days{Location: ny, StartHour: 4},
+			session: models.Session{StartTime: time.Date(2024, 6, 1, 22, 0, 0, 0, ny), EndTime: time.Date(2024, 6, 2, 2, 0, 0, 0, ny)},
+			parts:   []time.Duration{4 * time.Hour},
+		},
+		{
+			name:    "several days with the clocks going back",
+			days:    analytics.Days{Location: ny},
+			session: models.Session{StartTime: time.Date(2024, 11, 2, 12, 0, 0, 0, ny), EndTime: time.Date(2024, 11, 4, 12, 0, 0, 0, ny)},
+			parts:   []time.Duration{12 * time.Hour, 25 * time.Hour, 12 * time.Hour},
+		},
+		{
+			name:    "unfinished",
+			days:    analytics.Days{Location: ny},
+			session: models.Session{StartTime: time.Date(2024, 6, 1, 22, 0, 0, 0, ny)},
+			parts:   []time.Duration{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.session.Task = "test"
+			parts := tt.days.Split(tt.session)
+			require.Len(t, parts, len(tt.parts))
+
+			for i, part := range parts {
+				assert.Equal(t, "test", part.Task)
+				if !part.EndTime.IsZero() {
+					assert.Equal(t, tt.parts[i], part.EndTime.Sub(part.StartTime))
+				}
+				if i > 0 {
+					assert.Equal(t, parts[i-1].EndTime, part.StartTime)
+					assert.Equal(t, tt.days.Start(part.StartTime), part.StartTime)
+				}
+			}
+			assert.Equal(t, tt.session.StartTime, parts[0].StartTime)
+			assert.Equal(t, tt.session.EndTime, parts[len(parts)-1].EndTime)
+		})
+	}
+}
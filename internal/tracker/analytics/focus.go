@@ -0,0 +1,168 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+)
+
+// FocusGap is the longest pause between two sessions of the same task that
+// still counts as one focus block, e.g. a short break between Pomodoros
+const FocusGap = 5 * time.Minute
+
+// FocusBlock is a run of sessions of one task with no other task in between
+// and no pause longer than FocusGap
+type FocusBlock struct {
+	Task  string
+	Start time.Time
+	// Duration is the time spent in the sessions, pauses do not count
+	Duration time.Duration
+}
+
+// FocusDay holds the focus metrics of one day
+type FocusDay struct {
+	// Day is the start of the day
+	Day      time.Time
+	Sessions int
+	// Switches is the number of times a session is of another task than
+	// the one before it on the same day
+	Switches int
+	Total    time.Duration
+	Longest  FocusBlock
+	// Fragmentation is the average number of distinct tasks per hour with
+	// tracked time
+	Fragmentation float64
+}
+
+// AverageSession returns the average length of the sessions of the day
+func (d FocusDay) AverageSession() time.Duration {
+	if d.Sessions == 0 {
+		return 0
+	}
+	return d.Total / time.Duration(d.Sessions)
+}
+
+// Focus holds the context switching and fragmentation of the sessions
+// selected by a query, per day and overall
+type Focus struct {
+	// Days has one entry per day with tracked time, oldest first
+	Days     []FocusDay
+	Sessions int
+	Switches int
+	Total    time.Duration
+	Longest  FocusBlock
+	// Fragmentation is the average number of distinct tasks over every hour
+	// with tracked time
+	Fragmentation float64
+}
+
+// SwitchesPerDay returns the average number of switches per tracked day
+func (f *Focus) SwitchesPerDay() float64 {
+	if len(f.Days) == 0 {
+		return 0
+	}
+	return float64(f.Switches) / float64(len(f.Days))
+}
+
+// AverageSession returns the average length of the sessions
+func (f *Focus) AverageSession() time.Duration {
+	if f.Sessions == 0 {
+		return 0
+	}
+	return f.Total / time.Duration(f.Sessions)
+}
+
+// BuildFocus walks the sessions selected by a query in the order they were
+// tracked and measures, per day, how often the task changes, how long the
+// sessions and the focus blocks last and how many tasks share each hour.
+// Sessions running across days are split between them and each part counts
+// as a session of its day. The GroupBy of the query is ignored.
+func BuildFocus(ssns []models.Session, q Query) *Focus {
+	now := q.now()
+	var parts []models.Session
+	for _, ssn := range ssns {
+		if !q.matches(ssn) {
+			continue
+		}
+		if ssn, ok := q.clip(ssn, now); ok {
+			parts = append(parts, q.Days.Split(ssn)...)
+		}
+	}
+	sort.SliceStable(parts, func(i, j int) bool {
+		return parts[i].StartTime.Before(parts[j].StartTime)
+	})
+
+	f := &Focus{}
+	var day *FocusDay
+	var block FocusBlock
+	var last models.Session
+	for _, part := range parts {
+		start := q.Days.Start(part.StartTime)
+		if day == nil || !day.Day.Equal(start) {
+			f.Days = append(f.Days, FocusDay{Day: start})
+			day = &f.Days[len(f.Days)-1]
+			block = FocusBlock{}
+		} else if part.Task != last.Task {
+			day.Switches++
+		}
+
+		d := part.EndTime.Sub(part.StartTime)
+		if block.Task != part.Task || part.StartTime.Sub(last.EndTime) > FocusGap {
+			block = FocusBlock{Task: part.Task, Start: part.StartTime}
+		}
+		block.Duration += d
+		if block.Duration > day.Longest.Duration {
+			day.Longest = block
+		}
+
+		day.Sessions++
+		day.Total += d
+		last = part
+	}
+
+	hours, tasks := taskHours(parts, q.Days)
+	var allHours, allTasks int
+	for i := range f.Days {
+		day := &f.Days[i]
+		key := day.Day.Unix()
+		if hours[key] > 0 {
+			day.Fragmentation = float64(tasks[key]) / float64(hours[key])
+		}
+		f.Sessions += day.Sessions
+		f.Switches += day.Switches
+		f.Total += day.Total
+		if day.Longest.Duration > f.Longest.Duration {
+			f.Longest = day.Longest
+		}
+		allHours += hours[key]
+		allTasks += tasks[key]
+	}
+	if allHours > 0 {
+		f.Fragmentation = float64(allTasks) / float64(allHours)
+	}
+	return f
+}
+
+// taskHours returns, per day keyed by the Unix time of its start, the number
+// of hours with tracked time and the sum of the distinct tasks in each of them
+func taskHours(parts []models.Session, days Days) (hours, tasks map[int64]int) {
+	perHour := make(map[int64]map[string]bool)
+	dayOf := make(map[int64]int64)
+	for _, part := range days.splitHours(parts) {
+		t := part.StartTime.In(days.location())
+		hour := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location()).Unix()
+		if perHour[hour] == nil {
+			perHour[hour] = make(map[string]bool)
+			dayOf[hour] = days.Start(part.StartTime).Unix()
+		}
+		perHour[hour][part.Task] = true
+	}
+
+	hours, tasks = make(map[int64]int), make(map[int64]int)
+	for hour, set := range perHour {
+		hours[dayOf[hour]]++
+		tasks[dayOf[hour]] += len(set)
+	}
+	return hours, tasks
+}
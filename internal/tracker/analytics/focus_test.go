@@ -0,0 +1,64 @@
+package analytics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
+)
+
+func TestBuildFocus(t *testing.T) {
+	at := func(day, hour, minute int) time.Time { return time.Date(2024, 6, day, hour, minute, 0, 0, time.UTC) }
+	ssns := []models.Session{
+		// Two Pomodoros with a short break make one focus block
+		{Task: "coding", StartTime: at(3, 9, 0), EndTime: at(3, 9, 25)},
+		{Task: "coding", StartTime: at(3, 9, 30), EndTime: at(3, 9, 55)},
+		{Task: "email", StartTime: at(3, 9, 55), EndTime: at(3, 10, 15)},
+		{Task: "coding", StartTime: at(3, 10, 15), EndTime: at(3, 10, 45)},
+		// The long pause ends the block
+		{Task: "coding", StartTime: at(3, 11, 30), EndTime: at(3, 12, 0)},
+		{Task: "review", StartTime: at(4, 23, 0), EndTime: at(5, 1, 0)},
+	}
+	q := analytics.Query{Days: analytics.Days{Location: time.UTC}, Now: at(6, 0, 0)}
+
+	f := analytics.BuildFocus(ssns, q)
+	require.Len(t, f.Days, 3)
+
+	day := f.Days[0]
+	assert.Equal(t, at(3, 0, 0), day.Day)
+	assert.Equal(t, 5, day.Sessions)
+	assert.Equal(t, 2, day.Switches)
+	assert.Equal(t, 130*time.Minute, day.Total)
+	assert.Equal(t, 26*time.Minute, day.AverageSession())
+	assert.Equal(t, analytics.FocusBlock{Task: "coding", Start: at(3, 9, 0), Duration: 50 * time.Minute}, day.Longest)
+	assert.InDelta(t, 5.0/3, day.Fragmentation, 1e-9, "9:00 and 10:00 have two tasks, 11:00 one")
+
+	assert.Equal(t, 1, f.Days[1].Sessions, "Sessions across midnight are split between the days")
+	assert.Equal(t, time.Hour, f.Days[2].Total)
+
+	assert.Equal(t, 7, f.Sessions)
+	assert.Equal(t, 2, f.Switches)
+	assert.InDelta(t, 2.0/3, f.SwitchesPerDay(), 1e-9)
+	assert.Equal(t, analytics.FocusBlock{Task: "review", Start: at(4, 23, 0), Duration: time.Hour}, f.Longest)
+	assert.InDelta(t, 7.0/5, f.Fragmentation, 1e-9)
+}
+
+func TestBuildFocus_Filtered(t *testing.T) {
+	now := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	ssns := []models.Session{
+		{Task: "coding", Project: "gotrack", StartTime: now.Add(-3 * time.Hour), EndTime: now.Add(-2 * time.Hour)},
+		{Task: "email", StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(-time.Hour)},
+		{Task: "coding", Project: "gotrack", StartTime: now.Add(-time.Hour)},
+	}
+	q := analytics.Query{Project: "gotrack", Days: analytics.Days{Location: time.UTC}, Now: now}
+
+	f := analytics.BuildFocus(ssns, q)
+	assert.Equal(t, 2, f.Sessions)
+	assert.Zero(t, f.Switches)
+	assert.Equal(t, time.Hour, f.Longest.Duration, "The pause for the other project ends the block")
+	assert.Equal(t, 2*time.Hour, f.Total, "The running session counts up to now")
+}
@@ -0,0 +1,99 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+)
+
+// HeatmapDay is one day of a heatmap
+type HeatmapDay struct {
+	// Day is the start of the day
+	Day   time.Time
+	Total time.Duration
+	// Streak is true for the days of the longest streak
+	Streak bool
+}
+
+// Heatmap holds the time spent on every day of a range
+type Heatmap struct {
+	// From is the start of the first day, To the start of the day after the
+	// last one
+	From, To time.Time
+	// Days has one entry per day of the range, oldest first
+	Days []HeatmapDay
+	// Streak is the length of the longest run of consecutive tracked days in
+	// the range, its days are marked. Runs of a single day are not streaks.
+	Streak int
+}
+
+// DailyTotals returns the time spent per day between from and to, keyed by
+// Days.Key. Sessions crossing day boundaries are split between the days and
+// the running session counts up to now. An empty task counts all tasks.
+func DailyTotals(ssns []models.Session, task string, days Days, from, to, now time.Time) map[string]time.Duration {
+	totals := make(map[string]time.Duration)
+	for _, ssn := range ssns {
+		if task != "" && ssn.Task != task {
+			continue
+		}
+		ssn.EndTime = sessionEnd(ssn, now)
+		for _, part := range days.Split(ssn) {
+			if d := durationWithin(part, from, to, now); d > 0 {
+				totals[days.Key(part.StartTime)] += d
+			}
+		}
+	}
+	return totals
+}
+
+// BuildHeatmap returns the time spent per day from the day of from up to the
+// day before to, and marks the longest streak of tracked days among them
+func BuildHeatmap(ssns []models.Session, task string, days Days, from, to, now time.Time) *Heatmap {
+	h := &Heatmap{From: days.Start(from), To: to}
+	totals := DailyTotals(ssns, task, days, h.From, h.To, now)
+
+	keys := make([]string, 0, len(totals))
+	for key := range totals {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	first, length := longestStreak(keys)
+	last := first
+	for i := 1; i < length; i++ {
+		last = nextKey(last)
+	}
+	if length > 1 {
+		h.Streak = length
+	}
+
+	for day := h.From; day.Before(h.To); day = days.Next(day) {
+		key := days.Key(day)
+		h.Days = append(h.Days, HeatmapDay{
+			Day:    day,
+			Total:  totals[key],
+			Streak: h.Streak > 0 && key >= first && key <= last,
+		})
+	}
+	return h
+}
+
+// Tracked returns the number of days with tracked time
+func (h *Heatmap) Tracked() int {
+	n := 0
+	for _, d := range h.Days {
+		if d.Total > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Total returns the time spent over the whole range
+func (h *Heatmap) Total() time.Duration {
+	var total time.Duration
+	for _, d := range h.Days {
+		total += d.Total
+	}
+	return total
+}
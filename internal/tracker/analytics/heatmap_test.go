@@ -0,0 +1,76 @@
+package analytics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
+)
+
+func TestDailyTotals(t *testing.T) {
+	days := analytics.Days{Location: time.UTC}
+	day := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	now := day.Add(50 * time.Hour)
+	ssns := []models.Session{
+		{Task: "coding", StartTime: day.Add(22 * time.Hour), EndTime: day.Add(26 * time.Hour)},
+		{Task: "email", StartTime: day.Add(30 * time.Hour), EndTime: day.Add(31 * time.Hour)},
+		{Task: "coding", StartTime: now.Add(-time.Hour)},
+	}
+
+	totals := analytics.DailyTotals(ssns, "", days, day, day.Add(72*time.Hour), now)
+	assert.Equal(t, map[string]time.Duration{
+		"2024-06-03": 2 * time.Hour,
+		"2024-06-04": 3 * time.Hour,
+		"2024-06-05": time.Hour,
+	}, totals)
+
+	totals = analytics.DailyTotals(ssns, "coding", days, day.Add(24*time.Hour), day.Add(48*time.Hour), now)
+	assert.Equal(t, map[string]time.Duration{"2024-06-04": 2 * time.Hour}, totals)
+}
+
+func TestBuildHeatmap(t *testing.T) {
+	days := analytics.Days{Location: time.UTC}
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 11, 0, 0, 0, 0, time.UTC)
+	ssn := func(day, hours int) models.Session {
+		start := time.Date(2024, 6, day, 9, 0, 0, 0, time.UTC)
+		return models.Session{Task: "coding", StartTime: start, EndTime: start.Add(time.Duration(hours) * time.Hour)}
+	}
+	ssns := []models.Session{ssn(2, 1), ssn(3, 2), ssn(5, 1), ssn(6, 3), ssn(7, 1), ssn(9, 1), ssn(10, 1), ssn(10, 1)}
+
+	h := analytics.BuildHeatmap(ssns, "", days, from, to, to)
+	require.Len(t, h.Days, 10)
+	assert.Equal(t, from, h.Days[0].Day)
+	assert.Equal(t, 3, h.Streak)
+	assert.Equal(t, 7, h.Tracked())
+	assert.Equal(t, 11*time.Hour, h.Total())
+	assert.Equal(t, 2*time.Hour, h.Days[9].Total)
+
+	var streak []int
+	for _, d := range h.Days {
+		if d.Streak {
+			streak = append(streak, d.Day.Day())
+		}
+	}
+	assert.Equal(t, []int{5, 6, 7}, streak)
+
+	h = analytics.BuildHeatmap(ssns[:1], "", days, from, to, to)
+	assert.Zero(t, h.Streak, "A single day is not a streak")
+}
+
+func TestCalculateLongestStreak(t *testing.T) {
+	days := analytics.Days{Location: time.UTC}
+	ssn := func(month time.Month, day int) models.Session {
+		return models.Session{Task: "coding", StartTime: time.Date(2024, month, day, 9, 0, 0, 0, time.UTC)}
+	}
+
+	assert.Zero(t, analytics.CalculateLongestStreak(nil, days))
+	assert.Equal(t, 1, analytics.CalculateLongestStreak([]models.Session{ssn(1, 1)}, days))
+	assert.Equal(t, 3, analytics.CalculateLongestStreak([]models.Session{
+		ssn(2, 28), ssn(1, 1), ssn(2, 29), ssn(1, 2), ssn(3, 1), ssn(3, 1),
+	}, days))
+}
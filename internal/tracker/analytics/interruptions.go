@@ -0,0 +1,81 @@
+package analytics
+
+import (
+	"sort"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+)
+
+// InterruptionStats represents interruption counts for a day or a task
+type InterruptionStats struct {
+	Key      string
+	Internal int
+	External int
+	Voided   int
+}
+
+// Total returns the number of internal and external interruptions
+func (s InterruptionStats) Total() int {
+	return s.Internal + s.External
+}
+
+func (s *InterruptionStats) add(i models.Interruption) {
+	if i.External {
+		s.External++
+	} else {
+		s.Internal++
+	}
+	if i.Void {
+		s.Voided++
+	}
+}
+
+// InterruptionsPerDay returns interruption counts per day, oldest day first.
+// An empty task counts interruptions of all tasks.
+func InterruptionsPerDay(ints []models.Interruption, task string, days Days) []InterruptionStats {
+	byDay := make(map[string]*InterruptionStats)
+	for _, i := range ints {
+		if task != "" && i.Task != task {
+			continue
+		}
+		day := days.Key(i.Time)
+		if byDay[day] == nil {
+			byDay[day] = &InterruptionStats{Key: day}
+		}
+		byDay[day].add(i)
+	}
+
+	stats := collectInterruptionStats(byDay)
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Key < stats[j].Key
+	})
+	return stats
+}
+
+// InterruptionsPerTask returns interruption counts per task, most interrupted first
+func InterruptionsPerTask(ints []models.Interruption) []InterruptionStats {
+	byTask := make(map[string]*InterruptionStats)
+	for _, i := range ints {
+		if byTask[i.Task] == nil {
+			byTask[i.Task] = &InterruptionStats{Key: i.Task}
+		}
+		byTask[i.Task].add(i)
+	}
+
+	stats := collectInterruptionStats(byTask)
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Total() != stats[j].Total() {
+			return stats[i].Total() > stats[j].Total()
+		}
+		return stats[i].Key < stats[j].Key
+	})
+	return stats
+}
+
+func collectInterruptionStats(m map[string]*InterruptionStats) []InterruptionStats {
+	stats := make([]InterruptionStats, 0, len(m))
+	for _, s := range m {
+		stats = append(stats, *s)
+	}
+	return stats
+}
@@ -0,0 +1,61 @@
+package analytics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
+)
+
+func testInterruptions() []models.Interruption {
+	day1 := time.Date(2026, 3, 2, 10, 0, 0, 0, time.Local)
+	day2 := day1.AddDate(0, 0, 1)
+	return []models.Interruption{
+		{Task: "coding", Time: day2, External: true},
+		{Task: "coding", Time: day1},
+		{Task: "coding", Time: day1.Add(time.Hour), External: true, Void: true},
+		{Task: "writing", Time: day1.Add(2 * time.Hour)},
+	}
+}
+
+func TestInterruptionsPerDay(t *testing.T) {
+	tests := []struct {
+		name     string
+		task     string
+		expected []analytics.InterruptionStats
+	}{
+		{
+			name: "all tasks",
+			expected: []analytics.InterruptionStats{
+				{Key: "2026-03-02", Internal: 2, External: 1, Voided: 1},
+				{Key: "2026-03-03", External: 1},
+			},
+		},
+		{
+			name: "filter by task",
+			task: "writing",
+			expected: []analytics.InterruptionStats{
+				{Key: "2026-03-02", Internal: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, analytics.InterruptionsPerDay(testInterruptions(), tt.task, analytics.LocalDays()))
+		})
+	}
+}
+
+func TestInterruptionsPerTask(t *testing.T) {
+	stats := analytics.InterruptionsPerTask(testInterruptions())
+	assert.Equal(t, []analytics.InterruptionStats{
+		{Key: "coding", Internal: 1, External: 2, Voided: 1},
+		{Key: "writing", Internal: 1},
+	}, stats)
+	assert.Equal(t, 3, stats[0].Total())
+	assert.Empty(t, analytics.InterruptionsPerTask(nil))
+}
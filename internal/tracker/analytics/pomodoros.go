@@ -0,0 +1,149 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+)
+
+const dayLayout = "2006-01-02"
+
+// PomodoroDayStats represents pomodoro counts for a single day
+type PomodoroDayStats struct {
+	Day       string
+	Completed int
+	Abandoned int
+}
+
+// PomodorosPerDay returns pomodoro counts per day, oldest day first.
+// An empty task counts pomodoros of all tasks.
+func PomodorosPerDay(poms []models.Pomodoro, task string, days Days) []PomodoroDayStats {
+	byDay := make(map[string]*PomodoroDayStats)
+	for _, p := range poms {
+		if task != "" && p.Task != task {
+			continue
+		}
+		day := days.Key(p.StartTime)
+		if byDay[day] == nil {
+			byDay[day] = &PomodoroDayStats{Day: day}
+		}
+		if p.Completed {
+			byDay[day].Completed++
+		} else {
+			byDay[day].Abandoned++
+		}
+	}
+
+	stats := make([]PomodoroDayStats, 0, len(byDay))
+	for _, s := range byDay {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Day < stats[j].Day
+	})
+	return stats
+}
+
+// PomodoroCompletionRate returns the share of started pomodoros that were
+// completed, between 0 and 1. An empty task counts pomodoros of all tasks.
+func PomodoroCompletionRate(poms []models.Pomodoro, task string) float64 {
+	var started, completed int
+	for _, p := range poms {
+		if task != "" && p.Task != task {
+			continue
+		}
+		started++
+		if p.Completed {
+			completed++
+		}
+	}
+	if started == 0 {
+		return 0
+	}
+	return float64(completed) / float64(started)
+}
+
+// AveragePomodorosBeforeInterruption returns the average number of pomodoros
+// completed per day before the first interruption of that day. Days without
+// interruptions count all of their completed pomodoros. An empty task counts
+// pomodoros and interruptions of all tasks.
+func AveragePomodorosBeforeInterruption(poms []models.Pomodoro, ints []models.Interruption, task string, days Days) float64 {
+	firstInterruption := make(map[string]time.Time)
+	for _, i := range ints {
+		if task != "" && i.Task != task {
+			continue
+		}
+		day := days.Key(i.Time)
+		if first, ok := firstInterruption[day]; !ok || i.Time.Before(first) {
+			firstInterruption[day] = i.Time
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, p := range poms {
+		if task != "" && p.Task != task {
+			continue
+		}
+		day := days.Key(p.StartTime)
+		if _, ok := counts[day]; !ok {
+			counts[day] = 0
+		}
+		first, interrupted := firstInterruption[day]
+		if p.Completed && (!interrupted || !p.EndTime.After(first)) {
+			counts[day]++
+		}
+	}
+
+	if len(counts) == 0 {
+		return 0
+	}
+	total := 0
+	for _, c := range counts {
+		total += c
+	}
+	return float64(total) / float64(len(counts))
+}
+
+// PomodoroGoalStreaks returns the current and the longest run of consecutive
+// days on which at least goal pomodoros were completed. The current run ends
+// on the day of now, or the day before while that day's goal has not been
+// reached yet.
+func PomodoroGoalStreaks(perDay []PomodoroDayStats, goal int, days Days, now time.Time) (current, longest int) {
+	if goal <= 0 {
+		return 0, 0
+	}
+
+	met := make(map[string]bool)
+	for _, d := range perDay {
+		if d.Completed >= goal {
+			met[d.Day] = true
+		}
+	}
+
+	run := 0
+	prev := ""
+	for _, d := range perDay {
+		if !met[d.Day] {
+			run = 0
+			continue
+		}
+		if run > 0 && nextKey(prev) == d.Day {
+			run++
+		} else {
+			run = 1
+		}
+		prev = d.Day
+		longest = max(longest, run)
+	}
+
+	day := days.Key(now)
+	if !met[day] {
+		day = prevKey(day)
+	}
+	for met[day] {
+		current++
+		day = prevKey(day)
+	}
+	return current, longest
+}
@@ -0,0 +1,93 @@
+package analytics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
+)
+
+func testPomodoros() []models.Pomodoro {
+	day1 := time.Date(2026, 3, 2, 10, 0, 0, 0, time.Local)
+	day2 := day1.AddDate(0, 0, 1)
+	pom := func(task string, start time.Time, completed bool) models.Pomodoro {
+		return models.Pomodoro{
+			Task:      task,
+			StartTime: start,
+			EndTime:   start.Add(25 * time.Minute),
+			Planned:   25 * time.Minute,
+			Completed: completed,
+		}
+	}
+	return []models.Pomodoro{
+		pom("coding", day2, true),
+		pom("coding", day1, true),
+		pom("coding", day1.Add(30*time.Minute), true),
+		pom("writing", day1.Add(time.Hour), false),
+		pom("writing", day1.Add(2*time.Hour), true),
+	}
+}
+
+func TestPomodorosPerDay(t *testing.T) {
+	assert.Equal(t, []analytics.PomodoroDayStats{
+		{Day: "2026-03-02", Completed: 3, Abandoned: 1},
+		{Day: "2026-03-03", Completed: 1},
+	}, analytics.PomodorosPerDay(testPomodoros(), "", analytics.LocalDays()))
+
+	assert.Equal(t, []analytics.PomodoroDayStats{
+		{Day: "2026-03-02", Completed: 1, Abandoned: 1},
+	}, analytics.PomodorosPerDay(testPomodoros(), "writing", analytics.LocalDays()))
+}
+
+func TestPomodoroCompletionRate(t *testing.T) {
+	assert.InDelta(t, 0.8, analytics.PomodoroCompletionRate(testPomodoros(), ""), 0.001)
+	assert.InDelta(t, 0.5, analytics.PomodoroCompletionRate(testPomodoros(), "writing"), 0.001)
+	assert.Zero(t, analytics.PomodoroCompletionRate(nil, ""))
+}
+
+func TestAveragePomodorosBeforeInterruption(t *testing.T) {
+	// Day 1 is first interrupted after two completed pomodoros, day 2 never
+	ints := []models.Interruption{
+		{Task: "writing", Time: time.Date(2026, 3, 2, 11, 10, 0, 0, time.Local)},
+		{Task: "coding", Time: time.Date(2026, 3, 2, 12, 10, 0, 0, time.Local)},
+	}
+	assert.InDelta(t, 1.5, analytics.AveragePomodorosBeforeInterruption(testPomodoros(), ints, "", analytics.LocalDays()), 0.001)
+	assert.InDelta(t, 2.0, analytics.AveragePomodorosBeforeInterruption(testPomodoros(), nil, "", analytics.LocalDays()), 0.001)
+	assert.Zero(t, analytics.AveragePomodorosBeforeInterruption(nil, ints, "", analytics.LocalDays()))
+}
+
+func TestPomodoroGoalStreaks(t *testing.T) {
+	perDay := []analytics.PomodoroDayStats{
+		{Day: "2026-03-01", Completed: 8},
+		{Day: "2026-03-02", Completed: 9},
+		{Day: "2026-03-03", Completed: 8},
+		{Day: "2026-03-04", Completed: 3},
+		{Day: "2026-03-05", Completed: 8},
+		{Day: "2026-03-06", Completed: 8},
+	}
+
+	tests := []struct {
+		name             string
+		today            time.Time
+		current, longest int
+	}{
+		{name: "goal met today", today: time.Date(2026, 3, 6, 18, 0, 0, 0, time.Local), current: 2, longest: 3},
+		{name: "goal not met yet today", today: time.Date(2026, 3, 7, 9, 0, 0, 0, time.Local), current: 2, longest: 3},
+		{name: "streak broken", today: time.Date(2026, 3, 8, 9, 0, 0, 0, time.Local), current: 0, longest: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, longest := analytics.PomodoroGoalStreaks(perDay, 8, analytics.LocalDays(), tt.today)
+			assert.Equal(t, tt.current, current)
+			assert.Equal(t, tt.longest, longest)
+		})
+	}
+
+	current, longest := analytics.PomodoroGoalStreaks(perDay, 0, analytics.LocalDays(), time.Now())
+	assert.Zero(t, current)
+	assert.Zero(t, longest)
+}
@@ -0,0 +1,73 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+)
+
+// Profile is the time spent per hour of the day and per day of the week
+type Profile struct {
+	// Hours is indexed by the hour of the day in the timezone of the days
+	Hours [24]time.Duration
+	// Weekdays is indexed by time.Weekday, Sunday first
+	Weekdays [7]time.Duration
+	Total    time.Duration
+}
+
+// BuildProfile distributes the time of the sessions selected by a query over
+// the hours of the day and the days of the week. Sessions are split at every
+// hour, so a session from 9:30 to 11:15 adds 30 minutes to 9:00, an hour to
+// 10:00 and 15 minutes to 11:00. The GroupBy of the query is ignored.
+func BuildProfile(ssns []models.Session, q Query) *Profile {
+	now := q.now()
+	p := &Profile{}
+	for _, ssn := range ssns {
+		if !q.matches(ssn) {
+			continue
+		}
+		ssn, ok := q.clip(ssn, now)
+		if !ok {
+			continue
+		}
+		for _, part := range q.Days.splitHours(q.Days.Split(ssn)) {
+			d := part.EndTime.Sub(part.StartTime)
+			p.Hours[part.StartTime.In(q.Days.location()).Hour()] += d
+			p.Weekdays[q.Days.Start(part.StartTime).Weekday()] += d
+			p.Total += d
+		}
+	}
+	return p
+}
+
+// PeakHour returns the hour of the day with the most time spent, the
+// earliest one on ties
+func (p *Profile) PeakHour() int {
+	peak := 0
+	for h, d := range p.Hours {
+		if d > p.Hours[peak] {
+			peak = h
+		}
+	}
+	return peak
+}
+
+// PeakWeekday returns the day of the week with the most time spent, the
+// earliest one from Sunday on ties
+func (p *Profile) PeakWeekday() time.Weekday {
+	peak := time.Sunday
+	for wd, d := range p.Weekdays {
+		if d > p.Weekdays[peak] {
+			peak = time.Weekday(wd)
+		}
+	}
+	return peak
+}
+
+// Share returns the part of the total that d is, between 0 and 1
+func (p *Profile) Share(d time.Duration) float64 {
+	if p.Total == 0 {
+		return 0
+	}
+	return float64(d) / float64(p.Total)
+}
@@ -0,0 +1,51 @@
+package analytics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
+)
+
+func TestBuildProfile(t *testing.T) {
+	day := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC) // a Monday
+	ssns := []models.Session{
+		{Task: "coding", Tags: []string{"go"}, StartTime: day.Add(9*time.Hour + 30*time.Minute), EndTime: day.Add(11*time.Hour + 15*time.Minute)},
+		{Task: "email", StartTime: day.Add(23 * time.Hour), EndTime: day.Add(25 * time.Hour)},
+	}
+	query := analytics.Query{Days: analytics.Days{Location: time.UTC}}
+
+	p := analytics.BuildProfile(ssns, query)
+	assert.Equal(t, 30*time.Minute, p.Hours[9])
+	assert.Equal(t, time.Hour, p.Hours[10])
+	assert.Equal(t, 15*time.Minute, p.Hours[11])
+	assert.Equal(t, time.Hour, p.Hours[23])
+	assert.Equal(t, time.Hour, p.Hours[0])
+	assert.Equal(t, 2*time.Hour+45*time.Minute, p.Weekdays[time.Monday])
+	assert.Equal(t, time.Hour, p.Weekdays[time.Tuesday])
+	assert.Equal(t, 3*time.Hour+45*time.Minute, p.Total)
+	assert.Equal(t, 0, p.PeakHour(), "Ties go to the earliest hour")
+	assert.Equal(t, time.Monday, p.PeakWeekday())
+	assert.InDelta(t, 2.75/3.75, p.Share(p.Weekdays[time.Monday]), 1e-9)
+
+	query.Tags = []string{"go"}
+	p = analytics.BuildProfile(ssns, query)
+	assert.Equal(t, time.Hour+45*time.Minute, p.Total)
+	assert.Zero(t, p.Hours[23])
+}
+
+func TestBuildProfile_DayStartHour(t *testing.T) {
+	// With days starting at 4am, 1am on Tuesday still belongs to Monday
+	ssn := models.Session{
+		Task:      "coding",
+		StartTime: time.Date(2024, 6, 4, 1, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 6, 4, 2, 0, 0, 0, time.UTC),
+	}
+	p := analytics.BuildProfile([]models.Session{ssn}, analytics.Query{Days: analytics.Days{Location: time.UTC, StartHour: 4}})
+	assert.Equal(t, time.Hour, p.Weekdays[time.Monday])
+	assert.Equal(t, time.Hour, p.Hours[1])
+	assert.Zero(t, analytics.BuildProfile(nil, analytics.Query{}).Share(time.Hour))
+}
@@ -0,0 +1,324 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+)
+
+// GroupBy is a dimension reports are grouped by
+type GroupBy string
+
+// Dimensions reports can be grouped by
+const (
+	GroupDay     GroupBy = "day"
+	GroupWeek    GroupBy = "week"
+	GroupMonth   GroupBy = "month"
+	GroupTask    GroupBy = "task"
+	GroupTag     GroupBy = "tag"
+	GroupProject GroupBy = "project"
+	GroupWeekday GroupBy = "weekday"
+	GroupHour    GroupBy = "hour"
+)
+
+// GroupBys lists every dimension reports can be grouped by
+var GroupBys = []GroupBy{GroupDay, GroupWeek, GroupMonth, GroupTask, GroupTag, GroupProject, GroupWeekday, GroupHour}
+
+// NoValue is the group of sessions without a tag or a project
+const NoValue = "(none)"
+
+// ParseGroupBy parses the name of a dimension
+func ParseGroupBy(s string) (GroupBy, error) {
+	g := GroupBy(strings.ToLower(strings.TrimSpace(s)))
+	if !slices.Contains(GroupBys, g) {
+		return "", fmt.Errorf("unknown group %q, expected one of %s", s, joinGroupBys(GroupBys))
+	}
+	return g, nil
+}
+
+func joinGroupBys(groups []GroupBy) string {
+	names := make([]string, len(groups))
+	for i, g := range groups {
+		names[i] = string(g)
+	}
+	return strings.Join(names, ", ")
+}
+
+// splitsDays reports whether sessions have to be cut at day boundaries to be
+// grouped by g
+func (g GroupBy) splitsDays() bool {
+	return g == GroupDay || g == GroupWeek || g == GroupMonth || g == GroupWeekday || g == GroupHour
+}
+
+// Query selects the sessions of a report and how they are grouped
+type Query struct {
+	// Task, Project and Tags filter the sessions, sessions must have all of
+	// the tags. Empty values match every session.
+	Task    string
+	Project string
+	Tags    []string
+	// From and To limit the report to a time range, sessions crossing its
+	// bounds only count with their part within it. Zero values leave the
+	// range open.
+	From, To time.Time
+	// GroupBy lists the dimensions rows are grouped by, no dimensions gives
+	// a report with only the total
+	GroupBy []GroupBy
+	// ExcludeRunning leaves the running session out, otherwise it counts up
+	// to Now
+	ExcludeRunning bool
+	// Days sets the day boundaries of time groups and of the date range
+	Days Days
+	// Now is the current time, zero means time.Now()
+	Now time.Time
+}
+
+// Row holds the statistics of one group of a report
+type Row struct {
+	// Keys are the values of the group for each dimension of the query
+	Keys []string
+	// Count is the number of sessions in the group. A session spanning
+	// several groups, e.g. several days, counts once in each.
+	Count int
+	// Total is the time spent in the group
+	Total time.Duration
+
+	durations []time.Duration
+}
+
+func (r *Row) add(d time.Duration) {
+	r.Count++
+	r.Total += d
+	r.durations = append(r.durations, d)
+}
+
+// Average returns the average duration of the sessions in the group
+func (r Row) Average() time.Duration {
+	if r.Count == 0 {
+		return 0
+	}
+	return r.Total / time.Duration(r.Count)
+}
+
+// Percentile returns the duration that p percent of the sessions in the
+// group do not exceed, using the nearest-rank method
+func (r Row) Percentile(p float64) time.Duration {
+	if len(r.durations) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(r.durations)
+	slices.Sort(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	rank = min(max(rank, 1), len(sorted))
+	return sorted[rank-1]
+}
+
+// Report is the result of a query
+type Report struct {
+	GroupBy []GroupBy
+	Rows    []Row
+	// Total covers every selected session once
+	Total Row
+}
+
+// Run computes the report of a query over sessions
+func Run(ssns []models.Session, q Query) *Report {
+	now := q.now()
+	splitDays := slices.ContainsFunc(q.GroupBy, GroupBy.splitsDays)
+	splitHours := slices.Contains(q.GroupBy, GroupHour)
+
+	report := &Report{GroupBy: q.GroupBy}
+	rows := make(map[string]*Row)
+	for _, ssn := range ssns {
+		if !q.matches(ssn) {
+			continue
+		}
+		ssn, ok := q.clip(ssn, now)
+		if !ok {
+			continue
+		}
+		report.Total.add(ssn.EndTime.Sub(ssn.StartTime))
+		if len(q.GroupBy) == 0 {
+			continue
+		}
+
+		parts := []models.Session{ssn}
+		if splitDays {
+			parts = q.Days.Split(ssn)
+		}
+		if splitHours {
+			parts = q.Days.splitHours(parts)
+		}
+		for _, part := range parts {
+			for _, keys := range q.keys(part) {
+				id := strings.Join(keys, "\x00")
+				if rows[id] == nil {
+					rows[id] = &Row{Keys: keys}
+				}
+				rows[id].add(part.EndTime.Sub(part.StartTime))
+			}
+		}
+	}
+
+	for _, row := range rows {
+		report.Rows = append(report.Rows, *row)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		return q.less(report.Rows[i].Keys, report.Rows[j].Keys)
+	})
+	return report
+}
+
+func (q *Query) now() time.Time {
+	if q.Now.IsZero() {
+		return time.Now()
+	}
+	return q.Now
+}
+
+// matches reports whether a session passes the filters of the query
+func (q *Query) matches(ssn models.Session) bool {
+	if q.Task != "" && ssn.Task != q.Task {
+		return false
+	}
+	if q.Project != "" && ssn.Project != q.Project {
+		return false
+	}
+	for _, tag := range q.Tags {
+		if !slices.Contains(ssn.Tags, tag) {
+			return false
+		}
+	}
+	return !q.ExcludeRunning || !ssn.IsActive()
+}
+
+// clip ends the running session at now and cuts a session to the date range
+// of the query. It reports false when nothing of the session is left.
+func (q *Query) clip(ssn models.Session, now time.Time) (models.Session, bool) {
+	ssn.EndTime = sessionEnd(ssn, now)
+	if !q.From.IsZero() && ssn.StartTime.Before(q.From) {
+		ssn.StartTime = q.From
+	}
+	if !q.To.IsZero() && ssn.EndTime.After(q.To) {
+		ssn.EndTime = q.To
+	}
+	return ssn, ssn.EndTime.After(ssn.StartTime)
+}
+
+// keys returns the groups a part of a session falls in, one list of values
+// per group. Sessions with several tags fall in the group of every tag.
+func (q *Query) keys(part models.Session) [][]string {
+	keys := [][]string{{}}
+	for _, g := range q.GroupBy {
+		values := q.values(g, part)
+		next := make([][]string, 0, len(keys)*len(values))
+		for _, k := range keys {
+			for _, v := range values {
+				next = append(next, append(slices.Clone(k), v))
+			}
+		}
+		keys = next
+	}
+	return keys
+}
+
+func (q *Query) values(g GroupBy, part models.Session) []string {
+	switch g {
+	case GroupDay:
+		return []string{q.Days.Key(part.StartTime)}
+	case GroupWeek:
+		return []string{q.Days.WeekStart(part.StartTime).Format(dayLayout)}
+	case GroupMonth:
+		return []string{q.Days.MonthStart(part.StartTime).Format("2006-01")}
+	case GroupWeekday:
+		return []string{q.Days.Start(part.StartTime).Weekday().String()}
+	case GroupHour:
+		return []string{fmt.Sprintf("%02d:00", part.StartTime.In(q.Days.location()).Hour())}
+	case GroupProject:
+		if part.Project == "" {
+			return []string{NoValue}
+		}
+		return []string{part.Project}
+	case GroupTag:
+		if len(part.Tags) == 0 {
+			return []string{NoValue}
+		}
+		return part.Tags
+	default:
+		return []string{part.Task}
+	}
+}
+
+// less orders rows by their keys, weekdays from Sunday and everything else
+// alphabetically, which is chronological for dates and hours
+func (q *Query) less(a, b []string) bool {
+	for i, g := range q.GroupBy {
+		if a[i] == b[i] {
+			continue
+		}
+		if g == GroupWeekday {
+			return weekdayIndex(a[i]) < weekdayIndex(b[i])
+		}
+		return a[i] < b[i]
+	}
+	return false
+}
+
+func weekdayIndex(name string) int {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if d.String() == name {
+			return int(d)
+		}
+	}
+	return -1
+}
+
+// splitHours cuts parts of sessions at the start of every hour
+func (d Days) splitHours(parts []models.Session) []models.Session {
+	var split []models.Session
+	for _, part := range parts {
+		for start := part.StartTime; start.Before(part.EndTime); {
+			t := start.In(d.location())
+			end := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			if end.After(part.EndTime) {
+				end = part.EndTime
+			}
+			p := part
+			p.StartTime, p.EndTime = start, end
+			split = append(split, p)
+			start = end
+		}
+	}
+	return split
+}
+
+// ParseDay parses the day a date range begins or ends on and returns the
+// start of that day. It accepts dates such as 2024-03-31, "today",
+// "yesterday" and a number of days before today such as -7.
+func (d Days) ParseDay(s string, now time.Time) (time.Time, error) {
+	today := d.Start(now)
+	switch s = strings.ToLower(strings.TrimSpace(s)); {
+	case s == "today":
+		return today, nil
+	case s == "yesterday":
+		return d.at(today.Year(), today.Month(), today.Day()-1), nil
+	case strings.HasPrefix(s, "-"):
+		n, err := strconv.Atoi(strings.TrimSuffix(s[1:], "d"))
+		if err != nil || n < 0 {
+			return time.Time{}, fmt.Errorf("invalid day %q, expected a number of days such as -7", s)
+		}
+		return d.at(today.Year(), today.Month(), today.Day()-n), nil
+	}
+
+	day, err := time.ParseInLocation(dayLayout, s, d.location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid day %q, expected YYYY-MM-DD, today, yesterday or -N", s)
+	}
+	return d.at(day.Year(), day.Month(), day.Day()), nil
+}
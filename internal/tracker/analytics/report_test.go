@@ -0,0 +1,178 @@
+package analytics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
+)
+
+func testReportSessions() []models.Session {
+	day := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC) // a Monday
+	ssn := func(task, project string, tags []string, start, d time.Duration) models.Session {
+		return models.Session{
+			Task:      task,
+			Project:   project,
+			Tags:      tags,
+			StartTime: day.Add(start),
+			EndTime:   day.Add(start + d),
+		}
+	}
+	return []models.Session{
+		ssn("coding", "gotrack", []string{"go", "oss"}, 9*time.Hour, 2*time.Hour),
+		ssn("review", "gotrack", []string{"go"}, 14*time.Hour, 30*time.Minute),
+		ssn("email", "", nil, 23*time.Hour, 2*time.Hour),
+		ssn("coding", "gotrack", []string{"go", "oss"}, 33*time.Hour, time.Hour),
+	}
+}
+
+func reportKeys(r *analytics.Report) [][]string {
+	keys := make([][]string, len(r.Rows))
+	for i, row := range r.Rows {
+		keys[i] = row.Keys
+	}
+	return keys
+}
+
+func TestRun_GroupBy(t *testing.T) {
+	days := analytics.Days{Location: time.UTC}
+
+	tests := []struct {
+		name   string
+		group  []analytics.GroupBy
+		keys   [][]string
+		totals []time.Duration
+	}{
+		{
+			name:   "task",
+			group:  []analytics.GroupBy{analytics.GroupTask},
+			keys:   [][]string{{"coding"}, {"email"}, {"review"}},
+			totals: []time.Duration{3 * time.Hour, 2 * time.Hour, 30 * time.Minute},
+		},
+		{
+			name:   "day splits sessions at midnight",
+			group:  []analytics.GroupBy{analytics.GroupDay},
+			keys:   [][]string{{"2024-06-03"}, {"2024-06-04"}},
+			totals: []time.Duration{3*time.Hour + 30*time.Minute, 2 * time.Hour},
+		},
+		{
+			name:   "tag counts sessions once per tag",
+			group:  []analytics.GroupBy{analytics.GroupTag},
+			keys:   [][]string{{analytics.NoValue}, {"go"}, {"oss"}},
+			totals: []time.Duration{2 * time.Hour, 3*time.Hour + 30*time.Minute, 3 * time.Hour},
+		},
+		{
+			name:   "project and weekday",
+			group:  []analytics.GroupBy{analytics.GroupProject, analytics.GroupWeekday},
+			keys:   [][]string{{analytics.NoValue, "Monday"}, {analytics.NoValue, "Tuesday"}, {"gotrack", "Monday"}, {"gotrack", "Tuesday"}},
+			totals: []time.Duration{time.Hour, time.Hour, 2*time.Hour + 30*time.Minute, time.Hour},
+		},
+		{
+			name:   "hour",
+			group:  []analytics.GroupBy{analytics.GroupHour},
+			keys:   [][]string{{"00:00"}, {"09:00"}, {"10:00"}, {"14:00"}, {"23:00"}},
+			totals: []time.Duration{time.Hour, 2 * time.Hour, time.Hour, 30 * time.Minute, time.Hour},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := analytics.Run(testReportSessions(), analytics.Query{GroupBy: tt.group, Days: days})
+			assert.Equal(t, tt.keys, reportKeys(r))
+			for i, row := range r.Rows {
+				assert.Equal(t, tt.totals[i], row.Total, "row %v", row.Keys)
+			}
+			assert.Equal(t, 4, r.Total.Count)
+			assert.Equal(t, 5*time.Hour+30*time.Minute, r.Total.Total)
+		})
+	}
+}
+
+func TestRun_Filters(t *testing.T) {
+	days := analytics.Days{Location: time.UTC}
+	from := time.Date(2024, 6, 4, 0, 0, 0, 0, time.UTC)
+
+	r := analytics.Run(testReportSessions(), analytics.Query{From: from, Days: days})
+	assert.Equal(t, 2, r.Total.Count)
+	assert.Equal(t, 2*time.Hour, r.Total.Total, "Sessions crossing the start of the range only count after it")
+
+	r = analytics.Run(testReportSessions(), analytics.Query{To: from, Days: days})
+	assert.Equal(t, 3, r.Total.Count)
+	assert.Equal(t, 3*time.Hour+30*time.Minute, r.Total.Total)
+
+	r = analytics.Run(testReportSessions(), analytics.Query{Tags: []string{"go", "oss"}, Days: days})
+	assert.Equal(t, 2, r.Total.Count)
+
+	r = analytics.Run(testReportSessions(), analytics.Query{Project: "gotrack", Task: "review", Days: days})
+	assert.Equal(t, 1, r.Total.Count)
+	assert.Equal(t, 30*time.Minute, r.Total.Total)
+	assert.Empty(t, r.Rows, "A query without dimensions only has a total")
+}
+
+func TestRun_RunningSession(t *testing.T) {
+	now := time.Date(2024, 6, 5, 12, 0, 0, 0, time.UTC)
+	ssns := append(testReportSessions(), models.Session{Task: "coding", StartTime: now.Add(-time.Hour)})
+	query := analytics.Query{Days: analytics.Days{Location: time.UTC}, Now: now}
+
+	r := analytics.Run(ssns, query)
+	assert.Equal(t, 5, r.Total.Count)
+	assert.Equal(t, 6*time.Hour+30*time.Minute, r.Total.Total)
+
+	query.ExcludeRunning = true
+	r = analytics.Run(ssns, query)
+	assert.Equal(t, 4, r.Total.Count)
+}
+
+func TestRow_Aggregates(t *testing.T) {
+	r := analytics.Run(testReportSessions(), analytics.Query{Days: analytics.Days{Location: time.UTC}})
+	total := r.Total
+
+	assert.Equal(t, (5*time.Hour+30*time.Minute)/4, total.Average())
+	assert.Equal(t, 30*time.Minute, total.Percentile(0))
+	assert.Equal(t, time.Hour, total.Percentile(50))
+	assert.Equal(t, 2*time.Hour, total.Percentile(90))
+	assert.Equal(t, 2*time.Hour, total.Percentile(100))
+	assert.Zero(t, analytics.Row{}.Percentile(50))
+	assert.Zero(t, analytics.Row{}.Average())
+}
+
+func TestParseGroupBy(t *testing.T) {
+	g, err := analytics.ParseGroupBy(" Week ")
+	require.NoError(t, err)
+	assert.Equal(t, analytics.GroupWeek, g)
+
+	_, err = analytics.ParseGroupBy("fortnight")
+	assert.ErrorContains(t, err, "unknown group")
+}
+
+func TestDays_ParseDay(t *testing.T) {
+	days := analytics.Days{Location: time.UTC, StartHour: 4}
+	now := time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC) // still February 29th
+
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"today", time.Date(2024, 2, 29, 4, 0, 0, 0, time.UTC)},
+		{"yesterday", time.Date(2024, 2, 28, 4, 0, 0, 0, time.UTC)},
+		{"-7", time.Date(2024, 2, 22, 4, 0, 0, 0, time.UTC)},
+		{"-30d", time.Date(2024, 1, 30, 4, 0, 0, 0, time.UTC)},
+		{"2023-12-25", time.Date(2023, 12, 25, 4, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			day, err := days.ParseDay(tt.input, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, day)
+		})
+	}
+
+	for _, input := range []string{"tomorrow", "-x", "2023-13-01"} {
+		_, err := days.ParseDay(input, now)
+		assert.Error(t, err, input)
+	}
+}
@@ -0,0 +1,136 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+)
+
+// Components of the productivity score
+const (
+	ScoreVolume      = "volume"
+	ScoreConsistency = "consistency"
+	ScoreStreak      = "streak"
+)
+
+// MaxScore is the score reached when every target is met
+const MaxScore = 100.0
+
+// ScoreSettings are the window, the targets and the weights of the
+// productivity score
+type ScoreSettings struct {
+	// WindowDays is the number of days, up to today, the score looks at
+	WindowDays int
+	// VolumeTarget is the time tracked in the window for the full volume score
+	VolumeTarget time.Duration
+	// ActiveDaysTarget is the number of tracked days for the full
+	// consistency score
+	ActiveDaysTarget int
+	// StreakTarget is the length of the longest streak for the full streak score
+	StreakTarget int
+	// The weights are relative, they do not have to add up to 1
+	VolumeWeight, ConsistencyWeight, StreakWeight float64
+}
+
+// ScoreComponent is one part of the productivity score
+type ScoreComponent struct {
+	Name string
+	// Value is what was reached within the window and Target what gives the
+	// full score, in Unit ("hours" or "days")
+	Value, Target float64
+	Unit          string
+	// Ratio is the part of the target reached, at most 1
+	Ratio float64
+	// Weight is the share of the score the component counts for, the
+	// weights of all components add up to 1
+	Weight float64
+	// Points is what the component adds to the score
+	Points float64
+}
+
+// Missing returns the points the component would add by reaching its target
+func (c ScoreComponent) Missing() float64 {
+	return (1 - c.Ratio) * c.Weight * MaxScore
+}
+
+// Score is the productivity score over a window of days
+type Score struct {
+	// Window runs from the start of its first day to the end of today
+	Window     Period
+	Total      float64
+	Components []ScoreComponent
+}
+
+// Weakest returns the component that misses the most points, false when
+// every target is met
+func (s Score) Weakest() (ScoreComponent, bool) {
+	var weakest ScoreComponent
+	for _, c := range s.Components {
+		if c.Missing() > weakest.Missing() {
+			weakest = c
+		}
+	}
+	return weakest, weakest.Missing() > 0
+}
+
+// ScorePoint is the score as it was at the end of a day
+type ScorePoint struct {
+	// Day is the start of the day
+	Day   time.Time
+	Score float64
+}
+
+// CalculateScore returns the productivity score over the window of days
+// ending today. It adds up, by weight, how close the time tracked, the
+// number of tracked days and the longest streak of tracked days in the
+// window come to their targets. The running session counts up to now.
+func CalculateScore(ssns []models.Session, s ScoreSettings, days Days, now time.Time) Score {
+	today := days.Start(now)
+	score := Score{Window: Period{
+		From: days.at(today.Year(), today.Month(), today.Day()-s.WindowDays+1),
+		To:   days.Next(now),
+	}}
+
+	totals := DailyTotals(ssns, "", days, score.Window.From, score.Window.To, now)
+	var volume time.Duration
+	keys := make([]string, 0, len(totals))
+	for key, d := range totals {
+		volume += d
+		keys = append(keys, key)
+	}
+	_, streak := longestStreak(keys)
+
+	weights := s.VolumeWeight + s.ConsistencyWeight + s.StreakWeight
+	add := func(name string, value, target float64, unit string, weight float64) {
+		c := ScoreComponent{Name: name, Value: value, Target: target, Unit: unit}
+		if target > 0 {
+			c.Ratio = min(value/target, 1)
+		}
+		if weights > 0 {
+			c.Weight = weight / weights
+		}
+		c.Points = c.Ratio * c.Weight * MaxScore
+		score.Components = append(score.Components, c)
+		score.Total += c.Points
+	}
+	add(ScoreVolume, volume.Hours(), s.VolumeTarget.Hours(), "hours", s.VolumeWeight)
+	add(ScoreConsistency, float64(len(totals)), float64(s.ActiveDaysTarget), "days", s.ConsistencyWeight)
+	add(ScoreStreak, float64(streak), float64(s.StreakTarget), "days", s.StreakWeight)
+	return score
+}
+
+// ScoreHistory returns the score at the end of every step days back from
+// today, oldest first, with points entries. The last one is the score now.
+func ScoreHistory(ssns []models.Session, s ScoreSettings, days Days, now time.Time, points, step int) []ScorePoint {
+	today := days.Start(now)
+	history := make([]ScorePoint, 0, points)
+	for i := points - 1; i >= 0; i-- {
+		day := days.at(today.Year(), today.Month(), today.Day()-i*step)
+		at := now
+		if i > 0 {
+			at = days.Next(day).Add(-time.Nanosecond)
+		}
+		history = append(history, ScorePoint{Day: day, Score: CalculateScore(ssns, s, days, at).Total})
+	}
+	return history
+}
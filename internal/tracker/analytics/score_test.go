@@ -0,0 +1,90 @@
+package analytics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
+)
+
+var scoreSettings = analytics.ScoreSettings{
+	WindowDays:        7,
+	VolumeTarget:      10 * time.Hour,
+	ActiveDaysTarget:  4,
+	StreakTarget:      4,
+	VolumeWeight:      2,
+	ConsistencyWeight: 1,
+	StreakWeight:      1,
+}
+
+func TestCalculateScore(t *testing.T) {
+	days := analytics.Days{Location: time.UTC}
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	at := func(day, hour int) time.Time { return time.Date(2024, 6, day, hour, 0, 0, 0, time.UTC) }
+	ssns := []models.Session{
+		{Task: "old", StartTime: at(1, 9), EndTime: at(1, 17)},
+		{Task: "coding", StartTime: at(3, 22), EndTime: at(4, 2)},
+		{Task: "coding", StartTime: at(7, 9), EndTime: at(7, 10)},
+		{Task: "coding", StartTime: at(10, 10)},
+	}
+
+	score := analytics.CalculateScore(ssns, scoreSettings, days, now)
+	assert.Equal(t, analytics.Period{From: at(4, 0), To: at(11, 0)}, score.Window)
+	require.Len(t, score.Components, 3)
+
+	volume := score.Components[0]
+	assert.Equal(t, analytics.ScoreVolume, volume.Name)
+	assert.InDelta(t, 5.0, volume.Value, 1e-9, "Only the part of a session within the window counts, the running one up to now")
+	assert.InDelta(t, 0.5, volume.Ratio, 1e-9)
+	assert.InDelta(t, 0.5, volume.Weight, 1e-9, "Weights are relative")
+	assert.InDelta(t, 25.0, volume.Points, 1e-9)
+
+	consistency := score.Components[1]
+	assert.Equal(t, 3.0, consistency.Value)
+	assert.InDelta(t, 18.75, consistency.Points, 1e-9)
+
+	streak := score.Components[2]
+	assert.Equal(t, 1.0, streak.Value)
+	assert.InDelta(t, 6.25, streak.Points, 1e-9)
+
+	assert.InDelta(t, 50.0, score.Total, 1e-9)
+	weakest, ok := score.Weakest()
+	assert.True(t, ok)
+	assert.Equal(t, analytics.ScoreVolume, weakest.Name)
+	assert.InDelta(t, 25.0, weakest.Missing(), 1e-9)
+}
+
+func TestCalculateScore_Capped(t *testing.T) {
+	days := analytics.Days{Location: time.UTC}
+	now := time.Date(2024, 6, 10, 23, 0, 0, 0, time.UTC)
+	var ssns []models.Session
+	for day := 4; day <= 10; day++ {
+		start := time.Date(2024, 6, day, 9, 0, 0, 0, time.UTC)
+		ssns = append(ssns, models.Session{Task: "coding", StartTime: start, EndTime: start.Add(8 * time.Hour)})
+	}
+
+	score := analytics.CalculateScore(ssns, scoreSettings, days, now)
+	assert.InDelta(t, analytics.MaxScore, score.Total, 1e-9)
+	_, ok := score.Weakest()
+	assert.False(t, ok)
+}
+
+func TestScoreHistory(t *testing.T) {
+	days := analytics.Days{Location: time.UTC}
+	now := time.Date(2024, 6, 17, 12, 0, 0, 0, time.UTC)
+	ssns := []models.Session{
+		{Task: "coding", StartTime: time.Date(2024, 6, 5, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2024, 6, 5, 19, 0, 0, 0, time.UTC)},
+	}
+
+	history := analytics.ScoreHistory(ssns, scoreSettings, days, now, 3, 7)
+	require.Len(t, history, 3)
+	assert.Equal(t, time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC), history[0].Day)
+	assert.Zero(t, history[0].Score, "Sessions after the end of the day do not count")
+	assert.InDelta(t, 62.5, history[1].Score, 1e-9)
+	assert.Equal(t, time.Date(2024, 6, 17, 0, 0, 0, 0, time.UTC), history[2].Day)
+	assert.Zero(t, history[2].Score, "The session is out of the window")
+}
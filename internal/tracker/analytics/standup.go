@@ -0,0 +1,116 @@
+package analytics
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+)
+
+// WorkingDays tells working days from weekends and holidays
+type WorkingDays struct {
+	Weekdays []time.Weekday
+	// Holidays are the keys of the days off, as returned by Days.Key
+	Holidays []string
+}
+
+// PreviousWorkingDay returns the start of the last working day before the
+// day t falls in. It returns the day before when no day of the past year is
+// a working day.
+func (d Days) PreviousWorkingDay(t time.Time, w WorkingDays) time.Time {
+	start := d.Start(t)
+	for i := 1; i <= 366; i++ {
+		day := d.at(start.Year(), start.Month(), start.Day()-i)
+		if slices.Contains(w.Weekdays, day.Weekday()) && !slices.Contains(w.Holidays, d.Key(day)) {
+			return day
+		}
+	}
+	return d.at(start.Year(), start.Month(), start.Day()-1)
+}
+
+// StandupTask is the work done on a task during a day
+type StandupTask struct {
+	Task     string
+	Duration time.Duration
+	// Tags and Notes are those of the sessions of the task, in the order
+	// they were first used
+	Tags    []string
+	Notes   []string
+	Running bool
+	// First is when the task was first worked on during the day
+	First time.Time
+}
+
+// StandupProject is the work done on the tasks of a project during a day
+type StandupProject struct {
+	// Project is empty for sessions without a project
+	Project string
+	// Tasks are ordered by when they were first worked on
+	Tasks []StandupTask
+	Total time.Duration
+}
+
+// StandupDay is the work done during a day, per project
+type StandupDay struct {
+	// Day is the start of the day
+	Day time.Time
+	// Projects are ordered by name, sessions without a project last
+	Projects []StandupProject
+	Total    time.Duration
+}
+
+// BuildStandupDay returns the tasks worked on during the day starting at
+// day, grouped per project. Sessions crossing the bounds of the day only
+// count with their part within it and the running session counts up to now.
+func BuildStandupDay(ssns []models.Session, days Days, day, now time.Time) StandupDay {
+	sd := StandupDay{Day: day}
+	end := days.Next(day)
+
+	projects := make(map[string]map[string]*StandupTask)
+	for _, ssn := range ssns {
+		d := durationWithin(ssn, day, end, now)
+		if d <= 0 {
+			continue
+		}
+		if projects[ssn.Project] == nil {
+			projects[ssn.Project] = make(map[string]*StandupTask)
+		}
+		task := projects[ssn.Project][ssn.Task]
+		if task == nil {
+			task = &StandupTask{Task: ssn.Task, First: ssn.StartTime}
+			projects[ssn.Project][ssn.Task] = task
+		}
+		task.Duration += d
+		task.Running = task.Running || ssn.IsActive()
+		for _, tag := range ssn.Tags {
+			if !slices.Contains(task.Tags, tag) {
+				task.Tags = append(task.Tags, tag)
+			}
+		}
+		if ssn.Note != "" && !slices.Contains(task.Notes, ssn.Note) {
+			task.Notes = append(task.Notes, ssn.Note)
+		}
+	}
+
+	for name, tasks := range projects {
+		p := StandupProject{Project: name}
+		for _, task := range tasks {
+			p.Tasks = append(p.Tasks, *task)
+			p.Total += task.Duration
+		}
+		sort.Slice(p.Tasks, func(i, j int) bool {
+			return p.Tasks[i].First.Before(p.Tasks[j].First)
+		})
+		sd.Projects = append(sd.Projects, p)
+		sd.Total += p.Total
+	}
+	sort.Slice(sd.Projects, func(i, j int) bool {
+		a, b := sd.Projects[i].Project, sd.Projects[j].Project
+		if a == "" || b == "" {
+			return b == ""
+		}
+		return a < b
+	})
+	return sd
+}
@@ -0,0 +1,76 @@
+package analytics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
+)
+
+func TestDays_PreviousWorkingDay(t *testing.T) {
+	days := analytics.Days{Location: time.UTC}
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	date := func(day int) time.Time { return time.Date(2026, 10, day, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		holidays []string
+		expected time.Time
+	}{
+		{"weekday", date(14).Add(9 * time.Hour), nil, date(13)},
+		{"monday skips the weekend", date(19).Add(9 * time.Hour), nil, date(16)},
+		{"holidays are skipped", date(19).Add(9 * time.Hour), []string{"2026-10-16", "2026-10-15"}, date(14)},
+		{"weekend", date(18).Add(9 * time.Hour), nil, date(16)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := analytics.WorkingDays{Weekdays: weekdays, Holidays: tt.holidays}
+			assert.Equal(t, tt.expected, days.PreviousWorkingDay(tt.now, w))
+		})
+	}
+
+	assert.Equal(t, date(18), days.PreviousWorkingDay(date(19), analytics.WorkingDays{}), "Without working days the day before is used")
+}
+
+func TestBuildStandupDay(t *testing.T) {
+	days := analytics.Days{Location: time.UTC}
+	at := func(day, hour int) time.Time { return time.Date(2026, 10, day, hour, 0, 0, 0, time.UTC) }
+	now := at(19, 11)
+	ssns := []models.Session{
+		{Task: "email", StartTime: at(15, 23), EndTime: at(16, 1)},
+		{Task: "coding", Project: "gotrack", Tags: []string{"go"}, StartTime: at(16, 9), EndTime: at(16, 11), Note: "timesheet"},
+		{Task: "review", Project: "gotrack", StartTime: at(16, 11), EndTime: at(16, 12)},
+		{Task: "coding", Project: "gotrack", Tags: []string{"go", "oss"}, StartTime: at(16, 14), EndTime: at(16, 15), Note: "standup"},
+		{Task: "call", Project: "client-a", StartTime: at(16, 16), EndTime: at(16, 17)},
+		{Task: "coding", Project: "gotrack", StartTime: at(19, 9)},
+	}
+
+	day := analytics.BuildStandupDay(ssns, days, at(16, 0), now)
+	assert.Equal(t, 6*time.Hour, day.Total)
+	require.Len(t, day.Projects, 3)
+	assert.Equal(t, "client-a", day.Projects[0].Project)
+	assert.Equal(t, "", day.Projects[2].Project, "Sessions without a project come last")
+	assert.Equal(t, time.Hour, day.Projects[2].Total, "Only the part of the day counts")
+
+	gotrack := day.Projects[1]
+	assert.Equal(t, 4*time.Hour, gotrack.Total)
+	require.Len(t, gotrack.Tasks, 2)
+	assert.Equal(t, analytics.StandupTask{
+		Task:     "coding",
+		Duration: 3 * time.Hour,
+		Tags:     []string{"go", "oss"},
+		Notes:    []string{"timesheet", "standup"},
+		First:    at(16, 9),
+	}, gotrack.Tasks[0])
+	assert.Equal(t, "review", gotrack.Tasks[1].Task)
+
+	today := analytics.BuildStandupDay(ssns, days, at(19, 0), now)
+	require.Len(t, today.Projects, 1)
+	assert.True(t, today.Projects[0].Tasks[0].Running)
+	assert.Equal(t, 2*time.Hour, today.Total)
+}
@@ -0,0 +1,182 @@
+package analytics
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+)
+
+// Ways timesheet durations are rounded
+const (
+	RoundNone    = "none"
+	RoundNearest = "nearest"
+	RoundUp      = "up"
+	RoundDown    = "down"
+)
+
+// Entries timesheet rounding is applied to
+const (
+	RoundPerSession = "session"
+	RoundPerDay     = "day"
+)
+
+// Rounding sets how the time of a timesheet is rounded for billing
+type Rounding struct {
+	// Mode is one of RoundNone, RoundNearest, RoundUp or RoundDown
+	Mode      string
+	Increment time.Duration
+	// Per is RoundPerSession to round every session, or RoundPerDay to
+	// round the time of a row per day
+	Per string
+	// Minimum is the least time billed for an entry with tracked time
+	Minimum time.Duration
+}
+
+// Apply rounds the time of an entry to the increment and raises it to the
+// minimum. Entries without tracked time stay at zero.
+func (r Rounding) Apply(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	if r.Increment > 0 {
+		switch r.Mode {
+		case RoundNearest:
+			d = d.Round(r.Increment)
+		case RoundUp:
+			if rest := d % r.Increment; rest != 0 {
+				d += r.Increment - rest
+			}
+		case RoundDown:
+			d = d.Truncate(r.Increment)
+		}
+	}
+	return max(d, r.Minimum)
+}
+
+// TimesheetRow is the time billed on a task or a project per day of a week
+type TimesheetRow struct {
+	Key string
+	// Days is the time billed per day, Monday first
+	Days  [7]time.Duration
+	Total time.Duration
+	// Tracked is the time tracked, before rounding
+	Tracked time.Duration
+}
+
+// Timesheet is the time billed per task or project on each day of a week
+type Timesheet struct {
+	// Days are the starts of the days of the week, Monday first
+	Days [7]time.Time
+	By   GroupBy
+	Rows []TimesheetRow
+	// Totals is the time billed per day
+	Totals  [7]time.Duration
+	Total   time.Duration
+	Tracked time.Duration
+}
+
+// Week returns the ISO year and week number of the timesheet
+func (t *Timesheet) Week() (year, week int) {
+	return t.Days[0].ISOWeek()
+}
+
+// ISOWeek returns the start of the Monday of an ISO 8601 week, the week with
+// the first Thursday of the year being week 1
+func (d Days) ISOWeek(year, week int) (time.Time, error) {
+	jan4 := d.at(year, time.January, 4)
+	monday := d.at(year, time.January, 4-(int(jan4.Weekday())+6)%7+(week-1)*7)
+	if y, w := monday.ISOWeek(); week < 1 || y != year || w != week {
+		return time.Time{}, fmt.Errorf("%d has no week %d", year, week)
+	}
+	return monday, nil
+}
+
+// ParseWeek parses the week of a timesheet and returns the start of its
+// Monday. It accepts ISO weeks such as 2026-W41, "this" and "last"; an empty
+// string is this week.
+func (d Days) ParseWeek(s string, now time.Time) (time.Time, error) {
+	today := d.Start(now)
+	monday := d.at(today.Year(), today.Month(), today.Day()-(int(today.Weekday())+6)%7)
+	switch s = strings.ToLower(strings.TrimSpace(s)); s {
+	case "", "this":
+		return monday, nil
+	case "last":
+		return d.at(monday.Year(), monday.Month(), monday.Day()-7), nil
+	}
+
+	y, w, _ := strings.Cut(s, "-w")
+	year, yErr := strconv.Atoi(y)
+	week, wErr := strconv.Atoi(w)
+	if yErr != nil || wErr != nil {
+		return time.Time{}, fmt.Errorf("invalid week %q, expected e.g. 2026-W41, this or last", s)
+	}
+	return d.ISOWeek(year, week)
+}
+
+// BuildTimesheet returns the time spent on the sessions selected by a query
+// in the week starting on monday, per task or project as set by by and per
+// day, rounded as set by r. Sessions running across days are split between
+// them, and each part is rounded on its own when rounding per session. The
+// date range of the query is replaced by the week. Rows are ordered by key.
+func BuildTimesheet(ssns []models.Session, q Query, monday time.Time, by GroupBy, r Rounding) *Timesheet {
+	t := &Timesheet{By: by}
+	for i := range t.Days {
+		t.Days[i] = q.Days.at(monday.Year(), monday.Month(), monday.Day()+i)
+	}
+	q.From, q.To = t.Days[0], q.Days.at(monday.Year(), monday.Month(), monday.Day()+7)
+	now := q.now()
+
+	// tracked holds the time spent per row and day, billed the time billed
+	// when rounding per session
+	tracked := make(map[string]*[7]time.Duration)
+	billed := make(map[string]*[7]time.Duration)
+	for _, ssn := range ssns {
+		if !q.matches(ssn) {
+			continue
+		}
+		ssn, ok := q.clip(ssn, now)
+		if !ok {
+			continue
+		}
+		key := q.values(by, ssn)[0]
+		if tracked[key] == nil {
+			tracked[key], billed[key] = &[7]time.Duration{}, &[7]time.Duration{}
+		}
+		for _, part := range q.Days.Split(ssn) {
+			i := slices.IndexFunc(t.Days[:], q.Days.Start(part.StartTime).Equal)
+			if i < 0 {
+				// Not a day of the week, clip keeps this from happening
+				continue
+			}
+			d := part.EndTime.Sub(part.StartTime)
+			tracked[key][i] += d
+			billed[key][i] += r.Apply(d)
+		}
+	}
+
+	for key, days := range tracked {
+		row := TimesheetRow{Key: key}
+		for i, d := range days {
+			if r.Per == RoundPerSession {
+				row.Days[i] = billed[key][i]
+			} else {
+				row.Days[i] = r.Apply(d)
+			}
+			row.Total += row.Days[i]
+			row.Tracked += d
+			t.Totals[i] += row.Days[i]
+		}
+		t.Total += row.Total
+		t.Tracked += row.Tracked
+		t.Rows = append(t.Rows, row)
+	}
+	sort.Slice(t.Rows, func(i, j int) bool {
+		return t.Rows[i].Key < t.Rows[j].Key
+	})
+	return t
+}
@@ -0,0 +1,101 @@
+package analytics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
+)
+
+func TestRounding_Apply(t *testing.T) {
+	tests := []struct {
+		name     string
+		rounding analytics.Rounding
+		in, out  time.Duration
+	}{
+		{"none", analytics.Rounding{Mode: analytics.RoundNone, Increment: 15 * time.Minute}, 22 * time.Minute, 22 * time.Minute},
+		{"nearest down", analytics.Rounding{Mode: analytics.RoundNearest, Increment: 15 * time.Minute}, 22 * time.Minute, 15 * time.Minute},
+		{"nearest up", analytics.Rounding{Mode: analytics.RoundNearest, Increment: 6 * time.Minute}, 10 * time.Minute, 12 * time.Minute},
+		{"up", analytics.Rounding{Mode: analytics.RoundUp, Increment: 30 * time.Minute}, 31 * time.Minute, time.Hour},
+		{"up exact", analytics.Rounding{Mode: analytics.RoundUp, Increment: 30 * time.Minute}, time.Hour, time.Hour},
+		{"down", analytics.Rounding{Mode: analytics.RoundDown, Increment: 15 * time.Minute}, 29 * time.Minute, 15 * time.Minute},
+		{"minimum", analytics.Rounding{Mode: analytics.RoundDown, Increment: 15 * time.Minute, Minimum: 15 * time.Minute}, 5 * time.Minute, 15 * time.Minute},
+		{"nothing tracked", analytics.Rounding{Mode: analytics.RoundUp, Increment: 15 * time.Minute, Minimum: 15 * time.Minute}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.out, tt.rounding.Apply(tt.in))
+		})
+	}
+}
+
+func TestDays_ParseWeek(t *testing.T) {
+	days := analytics.Days{Location: time.UTC}
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC) // a Sunday
+
+	monday, err := days.ParseWeek("", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), monday, "Weeks begin on Monday")
+
+	monday, err = days.ParseWeek("last", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC), monday)
+
+	monday, err = days.ParseWeek("2026-W41", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC), monday)
+
+	monday, err = days.ParseWeek("2021-w1", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC), monday, "Week 1 has the first Thursday of the year")
+
+	monday, err = days.ParseWeek("2020-W53", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2020, 12, 28, 0, 0, 0, 0, time.UTC), monday)
+
+	for _, s := range []string{"2025-W53", "2026-W0", "2026-41", "2026-W41x"} {
+		_, err := days.ParseWeek(s, now)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestBuildTimesheet(t *testing.T) {
+	days := analytics.Days{Location: time.UTC}
+	monday := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	at := func(day, hour, minute int) time.Time { return time.Date(2026, 10, day, hour, minute, 0, 0, time.UTC) }
+	ssns := []models.Session{
+		{Task: "coding", Project: "gotrack", StartTime: at(4, 23, 0), EndTime: at(5, 0, 10)},
+		{Task: "coding", Project: "gotrack", StartTime: at(5, 9, 0), EndTime: at(5, 9, 10)},
+		{Task: "review", Project: "gotrack", StartTime: at(7, 14, 0), EndTime: at(7, 14, 50)},
+		{Task: "email", StartTime: at(11, 23, 30), EndTime: at(12, 0, 30)},
+	}
+	q := analytics.Query{Days: days, Now: at(18, 0, 0)}
+
+	perDay := analytics.Rounding{Mode: analytics.RoundUp, Increment: 15 * time.Minute, Per: analytics.RoundPerDay}
+	sheet := analytics.BuildTimesheet(ssns, q, monday, analytics.GroupProject, perDay)
+	assert.Equal(t, at(11, 0, 0), sheet.Days[6])
+	require.Len(t, sheet.Rows, 2)
+
+	assert.Equal(t, analytics.NoValue, sheet.Rows[0].Key)
+	assert.Equal(t, 30*time.Minute, sheet.Rows[0].Days[6], "Only the part within the week counts")
+	gotrack := sheet.Rows[1]
+	assert.Equal(t, "gotrack", gotrack.Key)
+	assert.Equal(t, 30*time.Minute, gotrack.Days[0], "20 minutes are rounded up once for the day")
+	assert.Equal(t, time.Hour, gotrack.Days[2])
+	assert.Equal(t, 90*time.Minute, gotrack.Total)
+	assert.Equal(t, 70*time.Minute, gotrack.Tracked)
+	assert.Equal(t, 2*time.Hour, sheet.Total)
+	assert.Equal(t, 100*time.Minute, sheet.Tracked)
+
+	perSession := perDay
+	perSession.Per = analytics.RoundPerSession
+	sheet = analytics.BuildTimesheet(ssns, q, monday, analytics.GroupTask, perSession)
+	require.Len(t, sheet.Rows, 3)
+	assert.Equal(t, "coding", sheet.Rows[0].Key)
+	assert.Equal(t, 30*time.Minute, sheet.Rows[0].Days[0], "Each session is rounded up on its own")
+	assert.Equal(t, [7]time.Duration{30 * time.Minute, 0, time.Hour, 0, 0, 0, 30 * time.Minute}, sheet.Totals)
+}
@@ -0,0 +1,146 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+)
+
+// Comparisons computed by Trends
+const (
+	TrendWeek          = "week"
+	TrendMonthLastYear = "month_last_year"
+	TrendRolling4Weeks = "rolling_4_weeks"
+)
+
+// Period is a range of time from From up to To
+type Period struct {
+	From, To time.Time
+}
+
+// Change compares the time spent in two periods
+type Change struct {
+	Current, Previous time.Duration
+}
+
+// Delta returns how much more time was spent in the current period
+func (c Change) Delta() time.Duration {
+	return c.Current - c.Previous
+}
+
+// Percent returns the delta in percent of the previous period. It reports
+// false when nothing was spent in the previous period.
+func (c Change) Percent() (float64, bool) {
+	if c.Previous == 0 {
+		return 0, false
+	}
+	return 100 * float64(c.Delta()) / float64(c.Previous), true
+}
+
+// TaskChange is the change of the time spent on a task
+type TaskChange struct {
+	Task string
+	Change
+}
+
+// Trend compares the time spent in the current period with earlier periods
+type Trend struct {
+	Name    string
+	Current Period
+	// Previous lists the periods compared with, their average counts as the
+	// previous time spent
+	Previous []Period
+	Total    Change
+	// Tasks are ordered by the time spent in the current period, then in the
+	// previous periods
+	Tasks []TaskChange
+}
+
+// Trends compares this week with last week, this month with the same month
+// last year, and this week with the average of the 4 weeks before. Periods
+// in progress are compared up to now with the same part of the earlier
+// periods, e.g. Sunday to Tuesday noon of this week with Sunday to Tuesday
+// noon of last week. The filters of the query apply, its range and GroupBy
+// are ignored.
+func Trends(ssns []models.Session, q Query) []Trend {
+	now := q.now()
+	d := q.Days
+
+	week := d.WeekStart(now)
+	sinceWeek := now.Sub(week)
+	weeksBefore := func(n int) Period {
+		start := d.at(week.Year(), week.Month(), week.Day()-7*n)
+		return Period{From: start, To: start.Add(sinceWeek)}
+	}
+
+	month := d.MonthStart(now)
+	lastYear := d.at(month.Year()-1, month.Month(), 1)
+	lastYearEnd := lastYear.Add(now.Sub(month))
+	if end := d.at(lastYear.Year(), lastYear.Month()+1, 1); lastYearEnd.After(end) {
+		lastYearEnd = end
+	}
+
+	return []Trend{
+		q.compare(ssns, TrendWeek, Period{week, now}, []Period{weeksBefore(1)}, now),
+		q.compare(ssns, TrendMonthLastYear, Period{month, now}, []Period{{lastYear, lastYearEnd}}, now),
+		q.compare(ssns, TrendRolling4Weeks, Period{week, now},
+			[]Period{weeksBefore(1), weeksBefore(2), weeksBefore(3), weeksBefore(4)}, now),
+	}
+}
+
+// compare builds the trend of the time spent per task in a period against
+// the average of earlier periods
+func (q Query) compare(ssns []models.Session, name string, current Period, previous []Period, now time.Time) Trend {
+	t := Trend{Name: name, Current: current, Previous: previous}
+
+	changes := make(map[string]*Change)
+	change := func(task string) *Change {
+		if changes[task] == nil {
+			changes[task] = &Change{}
+		}
+		return changes[task]
+	}
+	for task, d := range q.tasksWithin(ssns, current, now) {
+		change(task).Current = d
+	}
+	for _, p := range previous {
+		for task, d := range q.tasksWithin(ssns, p, now) {
+			change(task).Previous += d
+		}
+	}
+
+	for task, c := range changes {
+		c.Previous /= time.Duration(len(previous))
+		t.Total.Current += c.Current
+		t.Total.Previous += c.Previous
+		t.Tasks = append(t.Tasks, TaskChange{Task: task, Change: *c})
+	}
+	sort.Slice(t.Tasks, func(i, j int) bool {
+		a, b := t.Tasks[i], t.Tasks[j]
+		if a.Current != b.Current {
+			return a.Current > b.Current
+		}
+		if a.Previous != b.Previous {
+			return a.Previous > b.Previous
+		}
+		return a.Task < b.Task
+	})
+	return t
+}
+
+// tasksWithin returns the time spent per task on the sessions of the query
+// within a period
+func (q Query) tasksWithin(ssns []models.Session, p Period, now time.Time) map[string]time.Duration {
+	q.From, q.To = p.From, p.To
+	totals := make(map[string]time.Duration)
+	for _, ssn := range ssns {
+		if !q.matches(ssn) {
+			continue
+		}
+		if ssn, ok := q.clip(ssn, now); ok {
+			totals[ssn.Task] += ssn.EndTime.Sub(ssn.StartTime)
+		}
+	}
+	return totals
+}
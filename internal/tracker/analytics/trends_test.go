@@ -0,0 +1,87 @@
+package analytics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/analytics"
+)
+
+func testTrendSessions() []models.Session {
+	ssn := func(task string, year int, month time.Month, day, from, to int) models.Session {
+		return models.Session{
+			Task:      task,
+			StartTime: time.Date(year, month, day, from, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(year, month, day, to, 0, 0, 0, time.UTC),
+		}
+	}
+	return []models.Session{
+		ssn("coding", 2024, 6, 10, 9, 11),
+		ssn("coding", 2024, 6, 3, 9, 10),
+		ssn("coding", 2024, 6, 5, 9, 12), // later in last week than now in this week
+		ssn("email", 2024, 6, 4, 11, 13), // crosses the point of last week matching now
+		ssn("review", 2024, 5, 27, 9, 13),
+		ssn("coding", 2023, 6, 5, 9, 10),
+		ssn("coding", 2023, 6, 20, 9, 10),
+	}
+}
+
+func trendTasks(t analytics.Trend) map[string]analytics.Change {
+	tasks := make(map[string]analytics.Change)
+	for _, tc := range t.Tasks {
+		tasks[tc.Task] = tc.Change
+	}
+	return tasks
+}
+
+func TestTrends(t *testing.T) {
+	now := time.Date(2024, 6, 11, 12, 0, 0, 0, time.UTC) // a Tuesday
+	query := analytics.Query{Days: analytics.Days{Location: time.UTC}, Now: now}
+
+	trends := analytics.Trends(testTrendSessions(), query)
+	require.Len(t, trends, 3)
+
+	week := trends[0]
+	assert.Equal(t, analytics.TrendWeek, week.Name)
+	assert.Equal(t, analytics.Period{From: time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 6, 4, 12, 0, 0, 0, time.UTC)}, week.Previous[0])
+	assert.Equal(t, analytics.Change{Current: 2 * time.Hour, Previous: 2 * time.Hour}, week.Total)
+	assert.Equal(t, map[string]analytics.Change{
+		"coding": {Current: 2 * time.Hour, Previous: time.Hour},
+		"email":  {Previous: time.Hour},
+	}, trendTasks(week))
+
+	month := trends[1]
+	assert.Equal(t, analytics.TrendMonthLastYear, month.Name)
+	assert.Equal(t, map[string]analytics.Change{
+		"coding": {Current: 6 * time.Hour, Previous: time.Hour},
+		"email":  {Current: 2 * time.Hour},
+	}, trendTasks(month))
+
+	rolling := trends[2]
+	assert.Equal(t, analytics.TrendRolling4Weeks, rolling.Name)
+	assert.Len(t, rolling.Previous, 4)
+	assert.Equal(t, []analytics.TaskChange{
+		{Task: "coding", Change: analytics.Change{Current: 2 * time.Hour, Previous: 15 * time.Minute}},
+		{Task: "review", Change: analytics.Change{Previous: time.Hour}},
+		{Task: "email", Change: analytics.Change{Previous: 15 * time.Minute}},
+	}, rolling.Tasks)
+
+	query.Task = "email"
+	trends = analytics.Trends(testTrendSessions(), query)
+	assert.Equal(t, analytics.Change{Previous: time.Hour}, trends[0].Total)
+}
+
+func TestChange_Percent(t *testing.T) {
+	c := analytics.Change{Current: 3 * time.Hour, Previous: 2 * time.Hour}
+	assert.Equal(t, time.Hour, c.Delta())
+	percent, ok := c.Percent()
+	assert.True(t, ok)
+	assert.Equal(t, 50.0, percent)
+
+	_, ok = analytics.Change{Current: time.Hour}.Percent()
+	assert.False(t, ok, "No percentage without a previous time")
+}
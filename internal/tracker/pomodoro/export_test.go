@@ -0,0 +1,27 @@
+package pomodoro
+
+import "time"
+
+// SetClock replaces the clock used by the timer
+func (p *Pomodoro) SetClock(now func() time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.now = now
+}
+
+// Tick runs a single timer tick at the given time
+func (p *Pomodoro) Tick(now time.Time) bool {
+	return p.tick(now)
+}
+
+// Generation returns the generation of the current phase
+func (p *Pomodoro) Generation() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.generation
+}
+
+// CompleteSession completes the phase of the given generation
+func (p *Pomodoro) CompleteSession(completed bool, generation int) {
+	p.completeSession(completed, generation)
+}
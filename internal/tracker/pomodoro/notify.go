@@ -0,0 +1,35 @@
+package pomodoro
+
+import (
+	"fmt"
+
+	"github.com/AndriyBarskyi/gotrack/internal/notify"
+)
+
+// PhaseNotifier returns an AdvanceFunc that sends a notification whenever a
+// work phase or a break ends, whether the next phase starts right away or
+// waits for the timer to be started. Starting, pausing, resuming and stopping
+// the timer do not notify. Delivery errors are passed to onErr when it is not
+// nil.
+func PhaseNotifier(n notify.Notifier, onErr func(error)) AdvanceFunc {
+	return func(ended PhaseEnd, next Phase) {
+		var title string
+		switch ended.Phase.Kind {
+		case KindWork:
+			title = "Work session complete"
+		case KindBreak:
+			title = "Break is over"
+		default:
+			return
+		}
+
+		message := "Next: " + next.State().String()
+		if !next.OpenEnded() {
+			message = fmt.Sprintf("Next: %s (%s)", next.State(), next.Duration)
+		}
+
+		if err := n.Notify(title, message); err != nil && onErr != nil {
+			onErr(err)
+		}
+	}
+}
@@ -9,27 +9,66 @@ import (
 	"github.com/AndriyBarskyi/gotrack/internal/config"
 )
 
+const (
+	// tickInterval is how often the timer checks the phase deadline
+	tickInterval = 100 * time.Millisecond
+	// suspendThreshold is the gap between two ticks after which the process
+	// is assumed to have been suspended rather than just delayed
+	suspendThreshold = 5 * time.Second
+)
+
 // Callback functions type
 type (
 	StateChangeFunc func(State)
 	TickFunc        func(remaining time.Duration)
+	SuspendFunc     func(gap time.Duration)
+	PhaseEndFunc    func(PhaseEnd)
+	AdvanceFunc     func(ended PhaseEnd, next Phase)
 )
 
+// PhaseEnd describes a phase that is over, either because it ran its course
+// or because it was skipped, restarted or stopped
+type PhaseEnd struct {
+	Phase Phase
+	Start time.Time
+	End   time.Time
+	// Elapsed is the time spent in the phase, excluding pauses
+	Elapsed time.Duration
+	// Completed is true when the phase reached its deadline, or when an
+	// open-ended phase was ended by skipping it
+	Completed bool
+}
+
 // Pomodoro represents a Pomodoro timer instance
 type Pomodoro struct {
-	config       *config.PomodoroConfig
-	state        State
-	remaining    time.Duration
-	cycles       int
-	workSessions int
-
-	ticker   *time.Ticker
+	config     *config.PomodoroConfig
+	sequence   Sequence
+	phase      Phase
+	state      State
+	pausedFrom State
+	remaining  time.Duration
+	phaseStart time.Time
+	deadline   time.Time
+	pausedAt   time.Time
+	pausedFor  time.Duration
+	cycles     int
+	completed  int
+	// generation counts the phases begun, so that a completion requested for
+	// a phase that already ended, e.g. by a skip racing the deadline, is
+	// ignored
+	generation int
+
+	ticker     *time.Ticker
 	tickerQuit chan struct{}
-	lastTick time.Time
-	mu       sync.Mutex
+	lastTick   time.Time
+	now        func() time.Time
+	mu         sync.Mutex
 
 	onStateChange StateChangeFunc
 	onTick        TickFunc
+	onSuspend     SuspendFunc
+	onPhaseEnd    PhaseEndFunc
+	onAdvance     AdvanceFunc
 }
 
 // Config returns the Pomodoro configuration
@@ -40,17 +79,36 @@ func (p *Pomodoro) Config() *config.PomodoroConfig {
 // ErrAlreadyRunning is returned when trying to start an already running Pomodoro
 var ErrAlreadyRunning = errors.New("pomodoro is already running")
 
-// New creates a new Pomodoro timer with the given configuration
+// New creates a new Pomodoro timer running the classic sequence with the
+// given configuration
 func New(cfg *config.PomodoroConfig) *Pomodoro {
+	return NewWithSequence(cfg, Classic{Config: cfg})
+}
+
+// NewWithSequence creates a new Pomodoro timer running the given sequence
+func NewWithSequence(cfg *config.PomodoroConfig, seq Sequence) *Pomodoro {
+	first := seq.Next(Progress{})
 	return &Pomodoro{
 		config:        cfg,
+		sequence:      seq,
+		phase:         first,
 		state:         StateIdle,
-		remaining:     cfg.WorkDuration,
+		remaining:     first.Duration,
+		now:           wallClock,
 		onStateChange: func(State) {},
 		onTick:        func(time.Duration) {},
+		onSuspend:     func(time.Duration) {},
+		onPhaseEnd:    func(PhaseEnd) {},
+		onAdvance:     func(PhaseEnd, Phase) {},
 	}
 }
 
+// wallClock returns the current time without its monotonic reading, so that
+// differences keep counting while the machine is suspended
+func wallClock() time.Time {
+	return time.Now().Round(0)
+}
+
 // OnStateChange sets the callback for state changes
 func (p *Pomodoro) OnStateChange(fn StateChangeFunc) {
 	p.onStateChange = fn
@@ -61,35 +119,72 @@ func (p *Pomodoro) OnTick(fn TickFunc) {
 	p.onTick = fn
 }
 
-// Start starts the Pomodoro timer
+// OnSuspend sets the callback invoked when a suspend/resume of the machine is
+// detected. The gap is the time the timer was not ticking.
+func (p *Pomodoro) OnSuspend(fn SuspendFunc) {
+	p.onSuspend = fn
+}
+
+// OnPhaseEnd sets the callback invoked whenever a started phase ends
+func (p *Pomodoro) OnPhaseEnd(fn PhaseEndFunc) {
+	p.onPhaseEnd = fn
+}
+
+// OnAdvance sets the callback invoked when the timer moves on from a phase
+// to the next one, because the phase ran its course or was skipped. The next
+// phase may be waiting for Start, e.g. a break when breaks do not start
+// automatically. Restarting and stopping the timer do not advance it.
+func (p *Pomodoro) OnAdvance(fn AdvanceFunc) {
+	p.onAdvance = fn
+}
+
+// Start starts the Pomodoro timer, or resumes it when paused
 func (p *Pomodoro) Start() error {
 	p.mu.Lock()
-
-	if p.state == StateWorking || p.state == StateShortBreak || p.state == StateLongBreak {
+	if p.state.isRunning() {
 		p.mu.Unlock()
 		return fmt.Errorf("cannot start: timer is already running")
 	}
 
+	now := p.now()
 	if p.state == StatePaused {
-		p.state = StateWorking
+		p.resumeLocked(now)
 	} else {
-		p.remaining = p.config.WorkDuration
-		p.state = StateWorking
+		p.beginPhaseLocked(p.sequence.Next(Progress{}), now)
 	}
 	newState := p.state
 	p.mu.Unlock()
+
 	if p.onStateChange != nil {
 		p.onStateChange(newState)
 	}
-	p.startTicker()
 
+	p.startTicker()
 	return nil
 }
 
+// beginPhaseLocked makes ph the running phase, starting at now
+func (p *Pomodoro) beginPhaseLocked(ph Phase, now time.Time) {
+	p.generation++
+	p.phase = ph
+	p.state = ph.State()
+	p.remaining = ph.Duration
+	p.phaseStart = now
+	p.pausedFor = 0
+	p.deadline = now.Add(ph.Duration)
+}
+
+// resumeLocked continues the paused phase at now
+func (p *Pomodoro) resumeLocked(now time.Time) {
+	p.pausedFor += now.Sub(p.pausedAt)
+	p.state = p.pausedFrom
+	p.deadline = now.Add(p.remaining)
+}
+
 // Pause pauses the Pomodoro timer
 func (p *Pomodoro) Pause() {
 	p.mu.Lock()
-	if p.state != StateWorking && p.state != StateShortBreak && p.state != StateLongBreak {
+	if !p.state.isRunning() {
 		p.mu.Unlock()
 		return
 	}
@@ -98,9 +193,13 @@ func (p *Pomodoro) Pause() {
 		p.ticker.Stop()
 	}
 
+	p.remaining = p.remainingLocked()
+	p.pausedAt = p.now()
+	p.pausedFrom = p.state
 	p.state = StatePaused
 	newState := p.state
 	p.mu.Unlock()
+
 	if p.onStateChange != nil {
 		p.onStateChange(newState)
 	}
@@ -117,17 +216,86 @@ func (p *Pomodoro) Stop() {
 		close(p.tickerQuit)
 		p.tickerQuit = nil
 	}
+	ended, hasEnded := p.phaseEndLocked(false)
 	p.state = StateIdle
 	p.cycles = 0
-	p.workSessions = 0
+	p.completed = 0
 	p.lastTick = time.Time{}
+	p.phaseStart = time.Time{}
+	p.deadline = time.Time{}
+	p.pausedFor = 0
 	newState := p.state
 	p.mu.Unlock()
+
+	if hasEnded && p.onPhaseEnd != nil {
+		p.onPhaseEnd(ended)
+	}
 	if p.onStateChange != nil {
 		p.onStateChange(newState)
 	}
 }
 
+// Skip ends the current phase now and moves on to the next one. It is how
+// open-ended phases, such as flowtime work, are finished.
+func (p *Pomodoro) Skip() {
+	p.mu.Lock()
+	switch {
+	case p.state == StatePaused:
+		p.resumeLocked(p.now())
+	case !p.state.isRunning():
+		p.mu.Unlock()
+		return
+	}
+	completed, generation := p.phase.OpenEnded(), p.generation
+	p.mu.Unlock()
+
+	p.completeSession(completed, generation)
+}
+
+// Restart starts the running phase over with its full duration, e.g. after
+// the work interval was voided by an interruption
+func (p *Pomodoro) Restart() {
+	p.mu.Lock()
+	if !p.state.isRunning() {
+		p.mu.Unlock()
+		return
+	}
+
+	ended, _ := p.phaseEndLocked(false)
+	p.beginPhaseLocked(p.phase, p.now())
+	newState := p.state
+	p.mu.Unlock()
+
+	if p.onPhaseEnd != nil {
+		p.onPhaseEnd(ended)
+	}
+	if p.onStateChange != nil {
+		p.onStateChange(newState)
+	}
+}
+
+// Extend lengthens the current phase by d, or shortens it when d is negative.
+// A phase shortened past its end finishes on the next tick. Open-ended phases
+// have no deadline and are left unchanged.
+func (p *Pomodoro) Extend(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.phase.OpenEnded() {
+		return
+	}
+
+	switch {
+	case p.state.isRunning():
+		now := p.now()
+		p.deadline = p.deadline.Add(d)
+		if p.deadline.Before(now) {
+			p.deadline = now
+		}
+	case p.state == StatePaused:
+		p.remaining = max(p.remaining+d, 0)
+	}
+}
+
 // State returns the current state of the Pomodoro timer
 func (p *Pomodoro) State() State {
 	p.mu.Lock()
@@ -135,6 +303,13 @@ func (p *Pomodoro) State() State {
 	return p.state
 }
 
+// Phase returns the current phase, or the paused one while paused
+func (p *Pomodoro) Phase() Phase {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.phase
+}
+
 // Cycles returns the number of completed work sessions
 func (p *Pomodoro) Cycles() int {
 	p.mu.Lock()
@@ -142,11 +317,49 @@ func (p *Pomodoro) Cycles() int {
 	return p.cycles
 }
 
-// Remaining returns the remaining time in the current session
+// Remaining returns the remaining time in the current session.
+// While a phase is running it is derived from the phase deadline and the
+// clock, so it never drifts from wall time. Open-ended phases have no
+// remaining time.
 func (p *Pomodoro) Remaining() time.Duration {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	return p.remaining
+	return p.remainingLocked()
+}
+
+// Elapsed returns the time spent in the current phase, excluding pauses
+func (p *Pomodoro) Elapsed() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.elapsedLocked()
+}
+
+// PhaseStart returns when the current phase started
+func (p *Pomodoro) PhaseStart() time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.phaseStart
+}
+
+func (p *Pomodoro) remainingLocked() time.Duration {
+	if !p.state.isRunning() {
+		return p.remaining
+	}
+	if p.phase.OpenEnded() {
+		return 0
+	}
+	return max(p.deadline.Sub(p.now()), 0)
+}
+
+func (p *Pomodoro) elapsedLocked() time.Duration {
+	switch {
+	case p.state.isRunning():
+		return max(p.now().Sub(p.phaseStart)-p.pausedFor, 0)
+	case p.state == StatePaused:
+		return max(p.pausedAt.Sub(p.phaseStart)-p.pausedFor, 0)
+	default:
+		return 0
+	}
 }
 
 func (p *Pomodoro) startTicker() {
@@ -159,50 +372,88 @@ func (p *Pomodoro) startTicker() {
 		p.tickerQuit = nil
 	}
 
-	p.ticker = time.NewTicker(100 * time.Millisecond)
-	p.tickerQuit = make(chan struct{})
-	p.lastTick = time.Now()
+	ticker, quit := time.NewTicker(tickInterval), make(chan struct{})
+	p.ticker, p.tickerQuit = ticker, quit
+	p.lastTick = p.now()
 	p.mu.Unlock()
 
 	go func(localTicker *time.Ticker, quit <-chan struct{}) {
 		for {
 			select {
 			case <-localTicker.C:
-				p.mu.Lock()
-				if p.state != StateWorking && p.state != StateShortBreak && p.state != StateLongBreak {
-					p.mu.Unlock()
-					continue
-				}
-				p.remaining -= 100 * time.Millisecond
-				remaining := p.remaining
-				shouldContinue := false
-				if remaining <= 0 {
-					shouldContinue = p.state == StateWorking ||
-						(p.state == StateShortBreak && p.config.AutoStartBreak) ||
-						(p.state == StateLongBreak && p.config.AutoStartBreak)
-				}
-				p.mu.Unlock()
-
-				if p.onTick != nil {
-					p.onTick(remaining)
-				}
-
-				if remaining <= 0 {
-					p.completeSession()
-					if !shouldContinue {
-						return
-					}
+				if p.tick(p.now()) {
+					return
 				}
 			case <-quit:
 				return
 			}
 		}
-	}(p.ticker, p.tickerQuit)
+	}(ticker, quit)
+}
+
+// tick advances the timer to now and reports whether the current phase ended
+func (p *Pomodoro) tick(now time.Time) bool {
+	p.mu.Lock()
+	if !p.state.isRunning() {
+		p.mu.Unlock()
+		return false
+	}
+
+	var suspended time.Duration
+	if gap := now.Sub(p.lastTick); gap > suspendThreshold {
+		suspended = gap - tickInterval
+		if p.config.ExtendOnSuspend {
+			p.deadline = p.deadline.Add(suspended)
+			p.pausedFor += suspended
+		}
+	}
+	p.lastTick = now
+	openEnded, generation := p.phase.OpenEnded(), p.generation
+	remaining := max(p.deadline.Sub(now), 0)
+	if openEnded {
+		remaining = 0
+	}
+	p.mu.Unlock()
+
+	if suspended > 0 && p.onSuspend != nil {
+		p.onSuspend(suspended)
+	}
+	if p.onTick != nil {
+		p.onTick(remaining)
+	}
+
+	if openEnded || remaining > 0 {
+		return false
+	}
+	p.completeSession(true, generation)
+	return true
 }
 
-func (p *Pomodoro) completeSession() {
+// phaseEndLocked describes the current phase as ending now. It reports false
+// when no phase has been started.
+func (p *Pomodoro) phaseEndLocked(completed bool) (PhaseEnd, bool) {
+	if p.phaseStart.IsZero() || (!p.state.isRunning() && p.state != StatePaused) {
+		return PhaseEnd{}, false
+	}
+	return PhaseEnd{
+		Phase:     p.phase,
+		Start:     p.phaseStart,
+		End:       p.now(),
+		Elapsed:   p.elapsedLocked(),
+		Completed: completed,
+	}, true
+}
 
+// completeSession moves on from the phase of the given generation to the
+// next one. completed tells whether the phase that ends ran its course. It
+// does nothing when that phase already ended, so that the ticker and Skip
+// cannot both complete the same phase.
+func (p *Pomodoro) completeSession(completed bool, generation int) {
 	p.mu.Lock()
+	if !p.state.isRunning() || p.generation != generation {
+		p.mu.Unlock()
+		return
+	}
 	if p.ticker != nil {
 		p.ticker.Stop()
 		p.ticker = nil
@@ -212,28 +463,38 @@ func (p *Pomodoro) completeSession() {
 		p.tickerQuit = nil
 	}
 
-	switch p.state {
-	case StateWorking:
-		p.workSessions++
+	now := p.now()
+	ended, _ := p.phaseEndLocked(completed)
+	progress := Progress{
+		Completed: p.phase,
+		Elapsed:   p.elapsedLocked(),
+	}
+	if p.phase.Kind == KindWork {
 		p.cycles++
-
-		if p.workSessions > 0 && p.workSessions%p.config.LongBreakInterval == 0 {
-			p.remaining = p.config.LongBreak
-			p.state = StateLongBreak
-		} else {
-			p.remaining = p.config.BreakDuration
-			p.state = StateShortBreak
-		}
-
-	case StateShortBreak, StateLongBreak:
-		p.remaining = p.config.WorkDuration
-		p.state = StateWorking
 	}
-
+	p.completed++
+	progress.Cycles = p.cycles
+	progress.Index = p.completed
+
+	next := p.sequence.Next(progress)
+	p.beginPhaseLocked(next, now)
+
+	autoStart := p.config.AutoStartBreak || next.Kind == KindWork
+	if !autoStart {
+		// Wait for an explicit Start before counting down the break
+		p.pausedAt = now
+		p.pausedFrom = p.state
+		p.state = StatePaused
+	}
 	newState := p.state
-	autoStart := p.config.AutoStartBreak || p.state == StateWorking
 	p.mu.Unlock()
 
+	if p.onPhaseEnd != nil {
+		p.onPhaseEnd(ended)
+	}
+	if p.onAdvance != nil {
+		p.onAdvance(ended, next)
+	}
 	if p.onStateChange != nil {
 		p.onStateChange(newState)
 	}
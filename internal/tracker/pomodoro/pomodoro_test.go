@@ -1,21 +1,23 @@
 package pomodoro_test
 
 import (
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/AndriyBarskyi/gotrack/internal/config"
+	"github.com/AndriyBarskyi/gotrack/internal/notify"
 	"github.com/AndriyBarskyi/gotrack/internal/tracker/pomodoro"
 )
 
 // testConfig returns a default test configuration
 func testConfig() *config.PomodoroConfig {
 	return &config.PomodoroConfig{
-		WorkDuration:      25 * time.Minute,
-		BreakDuration:     5 * time.Minute,
-		LongBreak:         15 * time.Minute,
+		WorkDuration:      config.Duration(25 * time.Minute),
+		BreakDuration:     config.Duration(5 * time.Minute),
+		LongBreak:         config.Duration(15 * time.Minute),
 		LongBreakInterval: 4,
 		AutoStartBreak:    true,
 	}
@@ -112,7 +114,7 @@ func TestPauseResume(t *testing.T) {
 	err := p.Start()
 	assert.NoError(t, err, "Resume should not return an error")
 	assert.Equal(t, pomodoro.StateWorking, p.State(), "State should be working after resume")
-	assert.Equal(t, remaining, p.Remaining(), "Remaining time should be preserved after resume")
+	assert.InDelta(t, remaining, p.Remaining(), float64(50*time.Millisecond), "Remaining time should be preserved after resume")
 }
 
 func TestStateTransitions(t *testing.T) {
@@ -125,8 +127,8 @@ func TestStateTransitions(t *testing.T) {
 		p := newTestPomodoro()
 
 		workDuration := 2 * time.Second
-		p.Config().WorkDuration = workDuration
-		p.Config().BreakDuration = 1 * time.Second
+		p.Config().WorkDuration = config.Duration(workDuration)
+		p.Config().BreakDuration = config.Duration(time.Second)
 		p.Config().AutoStartBreak = true
 
 		t.Logf("Starting test with work duration: %v, break duration: %v",
@@ -168,7 +170,7 @@ func TestStateTransitions(t *testing.T) {
 				t.Fatalf("Expected state to change to short break, got: %s", state)
 			}
 
-			assert.Equal(t, p.Config().BreakDuration, p.Remaining(), "Remaining time should be break duration")
+			assert.InDelta(t, time.Duration(p.Config().BreakDuration), p.Remaining(), float64(50*time.Millisecond), "Remaining time should be break duration")
 
 		case <-time.After(workDuration + 2*time.Second):
 			t.Fatalf("Timed out waiting for short break state after %v. Current state: %s, remaining: %v",
@@ -200,7 +202,7 @@ func TestCallbacks(t *testing.T) {
 	t.Run("tick callback", func(t *testing.T) {
 		p := newTestPomodoro()
 
-		p.Config().WorkDuration = 2 * time.Second
+		p.Config().WorkDuration = config.Duration(2 * time.Second)
 
 		var tickCount int
 		var lastRemaining time.Duration
@@ -221,3 +223,296 @@ func TestCallbacks(t *testing.T) {
 		assert.Less(t, lastRemaining, p.Config().WorkDuration, "Remaining time should have decreased")
 	})
 }
+
+// fakeClock is a manually advanced clock for deadline tests
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	return c.now
+}
+
+func TestDeadlineTiming(t *testing.T) {
+	t.Run("remaining is derived from the clock", func(t *testing.T) {
+		clock := newFakeClock()
+		p := newTestPomodoro()
+		p.SetClock(clock.Now)
+
+		assert.NoError(t, p.Start())
+		clock.Advance(10 * time.Minute)
+		assert.Equal(t, 15*time.Minute, p.Remaining())
+		p.Stop()
+	})
+
+	t.Run("paused time does not count", func(t *testing.T) {
+		clock := newFakeClock()
+		p := newTestPomodoro()
+		p.SetClock(clock.Now)
+
+		assert.NoError(t, p.Start())
+		clock.Advance(5 * time.Minute)
+		p.Pause()
+		clock.Advance(time.Hour)
+		assert.Equal(t, 20*time.Minute, p.Remaining())
+
+		assert.NoError(t, p.Start())
+		clock.Advance(time.Minute)
+		assert.Equal(t, 19*time.Minute, p.Remaining())
+		p.Stop()
+	})
+
+	t.Run("resume restores the paused phase", func(t *testing.T) {
+		clock := newFakeClock()
+		p := newTestPomodoro()
+		p.SetClock(clock.Now)
+
+		assert.NoError(t, p.Start())
+		p.Tick(clock.Advance(25 * time.Minute))
+		assert.Equal(t, pomodoro.StateShortBreak, p.State())
+
+		p.Pause()
+		assert.NoError(t, p.Start())
+		assert.Equal(t, pomodoro.StateShortBreak, p.State())
+		p.Stop()
+	})
+}
+
+func TestExtend(t *testing.T) {
+	t.Run("extends the running phase", func(t *testing.T) {
+		clock := newFakeClock()
+		p := newTestPomodoro()
+		p.SetClock(clock.Now)
+
+		assert.NoError(t, p.Start())
+		clock.Advance(10 * time.Minute)
+		p.Extend(5 * time.Minute)
+		assert.Equal(t, 20*time.Minute, p.Remaining())
+
+		p.Tick(clock.Advance(15 * time.Minute))
+		assert.Equal(t, pomodoro.StateWorking, p.State(), "Extended phase should still be running")
+		p.Stop()
+	})
+
+	t.Run("shortening past the end finishes the phase", func(t *testing.T) {
+		clock := newFakeClock()
+		p := newTestPomodoro()
+		p.SetClock(clock.Now)
+
+		assert.NoError(t, p.Start())
+		p.Extend(-time.Hour)
+		assert.Equal(t, time.Duration(0), p.Remaining())
+
+		p.Tick(clock.Advance(time.Second))
+		assert.Equal(t, pomodoro.StateShortBreak, p.State())
+		p.Stop()
+	})
+
+	t.Run("adjusts the paused phase", func(t *testing.T) {
+		clock := newFakeClock()
+		p := newTestPomodoro()
+		p.SetClock(clock.Now)
+
+		assert.NoError(t, p.Start())
+		clock.Advance(5 * time.Minute)
+		p.Pause()
+		p.Extend(-time.Minute)
+		assert.Equal(t, 19*time.Minute, p.Remaining())
+
+		assert.NoError(t, p.Start())
+		assert.Equal(t, 19*time.Minute, p.Remaining())
+		p.Stop()
+	})
+}
+
+func TestPhaseEnd(t *testing.T) {
+	clock := newFakeClock()
+	p := newTestPomodoro()
+	p.SetClock(clock.Now)
+
+	var ended []pomodoro.PhaseEnd
+	p.OnPhaseEnd(func(e pomodoro.PhaseEnd) {
+		ended = append(ended, e)
+	})
+
+	assert.NoError(t, p.Start())
+	start := clock.Now()
+	p.Tick(clock.Advance(25 * time.Minute))
+	clock.Advance(2 * time.Minute)
+	p.Skip()
+	clock.Advance(10 * time.Minute)
+	p.Restart()
+	clock.Advance(time.Minute)
+	p.Stop()
+
+	if assert.Len(t, ended, 4) {
+		assert.Equal(t, pomodoro.PhaseEnd{
+			Phase:     pomodoro.Phase{Name: "working", Kind: pomodoro.KindWork, Duration: 25 * time.Minute},
+			Start:     start,
+			End:       start.Add(25 * time.Minute),
+			Elapsed:   25 * time.Minute,
+			Completed: true,
+		}, ended[0])
+		assert.Equal(t, pomodoro.KindBreak, ended[1].Phase.Kind)
+		assert.False(t, ended[1].Completed, "Skipped break should not be completed")
+		assert.Equal(t, 10*time.Minute, ended[2].Elapsed)
+		assert.False(t, ended[2].Completed, "Restarted work should not be completed")
+		assert.Equal(t, time.Minute, ended[3].Elapsed)
+		assert.False(t, ended[3].Completed, "Stopped work should not be completed")
+	}
+}
+
+func TestSuspendDetection(t *testing.T) {
+	t.Run("suspend completes an expired phase", func(t *testing.T) {
+		clock := newFakeClock()
+		p := newTestPomodoro()
+		p.SetClock(clock.Now)
+
+		gaps := make(chan time.Duration, 1)
+		p.OnSuspend(func(gap time.Duration) { gaps <- gap })
+
+		assert.NoError(t, p.Start())
+		p.Tick(clock.Advance(40 * time.Minute))
+
+		assert.Equal(t, pomodoro.StateShortBreak, p.State())
+		assert.Equal(t, 1, p.Cycles())
+		assert.Greater(t, <-gaps, 39*time.Minute)
+		p.Stop()
+	})
+
+	t.Run("suspend extends the phase when configured", func(t *testing.T) {
+		clock := newFakeClock()
+		p := newTestPomodoro()
+		p.Config().ExtendOnSuspend = true
+		p.SetClock(clock.Now)
+
+		assert.NoError(t, p.Start())
+		p.Tick(clock.Advance(40 * time.Minute))
+
+		assert.Equal(t, pomodoro.StateWorking, p.State())
+		assert.InDelta(t, 25*time.Minute, p.Remaining(), float64(time.Second))
+		p.Stop()
+	})
+}
+
+func TestPhaseNotifier(t *testing.T) {
+	clock := newFakeClock()
+	p := newTestPomodoro()
+	p.SetClock(clock.Now)
+
+	rec := &notify.Recorder{}
+	p.OnAdvance(pomodoro.PhaseNotifier(rec, nil))
+
+	assert.NoError(t, p.Start())
+	assert.Empty(t, rec.Calls(), "Starting the timer should not notify")
+
+	p.Tick(clock.Advance(25 * time.Minute))
+	p.Tick(clock.Advance(5 * time.Minute))
+	p.Stop()
+
+	assert.Equal(t, []notify.Notification{
+		{Title: "Work session complete", Message: "Next: short break (5m0s)"},
+		{Title: "Break is over", Message: "Next: working (25m0s)"},
+	}, rec.Calls())
+}
+
+func TestPhaseNotifier_BreakNotAutoStarted(t *testing.T) {
+	clock := newFakeClock()
+	p := newTestPomodoro()
+	p.Config().AutoStartBreak = false
+	p.SetClock(clock.Now)
+
+	rec := &notify.Recorder{}
+	p.OnAdvance(pomodoro.PhaseNotifier(rec, nil))
+
+	assert.NoError(t, p.Start())
+	p.Tick(clock.Advance(25 * time.Minute))
+	assert.Equal(t, pomodoro.StatePaused, p.State(), "The break should wait to be started")
+	assert.Equal(t, []notify.Notification{
+		{Title: "Work session complete", Message: "Next: short break (5m0s)"},
+	}, rec.Calls())
+
+	assert.NoError(t, p.Start())
+	assert.Len(t, rec.Calls(), 1, "Starting the waiting break should not notify")
+	p.Tick(clock.Advance(5 * time.Minute))
+	p.Stop()
+
+	assert.Equal(t, []notify.Notification{
+		{Title: "Work session complete", Message: "Next: short break (5m0s)"},
+		{Title: "Break is over", Message: "Next: working (25m0s)"},
+	}, rec.Calls())
+}
+
+func TestSkipAtDeadline(t *testing.T) {
+	t.Run("stale completion is ignored", func(t *testing.T) {
+		clock := newFakeClock()
+		p := newTestPomodoro()
+		p.SetClock(clock.Now)
+
+		assert.NoError(t, p.Start())
+		clock.Advance(25 * time.Minute)
+		work := p.Generation()
+		p.Skip()
+		p.CompleteSession(true, work)
+
+		assert.Equal(t, pomodoro.StateShortBreak, p.State(), "The break should not be skipped as well")
+		assert.Equal(t, 1, p.Cycles())
+		p.Stop()
+	})
+
+	t.Run("skip racing the ticker", func(t *testing.T) {
+		clock := newFakeClock()
+		p := newTestPomodoro()
+		p.SetClock(clock.Now)
+
+		var ended []pomodoro.PhaseEnd
+		var mu sync.Mutex
+		p.OnPhaseEnd(func(e pomodoro.PhaseEnd) {
+			mu.Lock()
+			defer mu.Unlock()
+			ended = append(ended, e)
+		})
+
+		assert.NoError(t, p.Start())
+		now := clock.Advance(25 * time.Minute)
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			p.Tick(now)
+		}()
+		go func() {
+			defer wg.Done()
+			p.Skip()
+		}()
+		wg.Wait()
+
+		// The skip either ends the work with the deadline or, coming after
+		// it, skips the break; the work is never completed twice
+		assert.Equal(t, 1, p.Cycles(), "The pomodoro should be counted once")
+		mu.Lock()
+		var work int
+		for _, e := range ended {
+			if e.Phase.Kind == pomodoro.KindWork {
+				work++
+			}
+		}
+		assert.Equal(t, 1, work)
+		mu.Unlock()
+		p.Stop()
+	})
+}
@@ -0,0 +1,171 @@
+package pomodoro
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AndriyBarskyi/gotrack/internal/config"
+)
+
+// Built-in timer modes
+const (
+	ModeClassic  = config.ModeClassic
+	ModeFlowtime = config.ModeFlowtime
+)
+
+// Phase is one step of a focus sequence
+type Phase struct {
+	Name string
+	Kind Kind
+	// Duration is the length of the phase; zero means the phase is
+	// open-ended and runs until it is skipped
+	Duration time.Duration
+}
+
+// State returns the timer state while the phase is running
+func (ph Phase) State() State {
+	return State{Kind: ph.Kind, Name: ph.Name}
+}
+
+// OpenEnded reports whether the phase runs until it is skipped
+func (ph Phase) OpenEnded() bool {
+	return ph.Duration <= 0
+}
+
+// Progress describes where the timer is in its sequence
+type Progress struct {
+	// Completed is the phase that just ended; it is the zero Phase when the timer starts
+	Completed Phase
+	// Elapsed is the time actually spent in the completed phase
+	Elapsed time.Duration
+	// Cycles is the number of completed work phases
+	Cycles int
+	// Index is the number of completed phases
+	Index int
+}
+
+// Sequence decides which phases the timer runs through
+type Sequence interface {
+	// Next returns the phase that follows the completed one
+	Next(p Progress) Phase
+}
+
+// Classic is the classic Pomodoro sequence: work and short breaks, with a
+// long break after every LongBreakInterval work sessions
+type Classic struct {
+	Config *config.PomodoroConfig
+}
+
+// Next implements Sequence
+func (c Classic) Next(p Progress) Phase {
+	if p.Completed.Kind != KindWork {
+		return Phase{Name: StateWorking.Name, Kind: KindWork, Duration: time.Duration(c.Config.WorkDuration)}
+	}
+	if c.Config.LongBreakInterval > 0 && p.Cycles%c.Config.LongBreakInterval == 0 {
+		return Phase{Name: StateLongBreak.Name, Kind: KindBreak, Duration: time.Duration(c.Config.LongBreak)}
+	}
+	return Phase{Name: StateShortBreak.Name, Kind: KindBreak, Duration: time.Duration(c.Config.BreakDuration)}
+}
+
+// Fixed repeats a fixed list of phases, e.g. 52 minutes of work and 17
+// minutes of break
+type Fixed struct {
+	Phases []Phase
+}
+
+// Next implements Sequence
+func (f Fixed) Next(p Progress) Phase {
+	return f.Phases[p.Index%len(f.Phases)]
+}
+
+// Flowtime runs open-ended work phases followed by breaks proportional to
+// the time worked
+type Flowtime struct {
+	// BreakRatio is the amount of work per minute of break, e.g. 5 gives
+	// a 5 minute break after 25 minutes of work
+	BreakRatio float64
+	MinBreak   time.Duration
+	MaxBreak   time.Duration
+}
+
+// Next implements Sequence
+func (f Flowtime) Next(p Progress) Phase {
+	if p.Completed.Kind != KindWork {
+		return Phase{Name: "flow", Kind: KindWork}
+	}
+
+	brk := p.Elapsed
+	if f.BreakRatio > 0 {
+		brk = time.Duration(float64(p.Elapsed) / f.BreakRatio)
+	}
+	brk = max(brk, f.MinBreak)
+	if f.MaxBreak > 0 {
+		brk = min(brk, f.MaxBreak)
+	}
+	// A break of zero would be open-ended and never end
+	brk = max(brk.Round(time.Second), time.Second)
+	return Phase{Name: "break", Kind: KindBreak, Duration: brk}
+}
+
+// NewSequence returns the sequence selected by cfg.Mode: "classic" (the
+// default), "flowtime", a work/break ratio in minutes such as "52/17", or the
+// name of a sequence defined in cfg.Sequences
+func NewSequence(cfg *config.PomodoroConfig) (Sequence, error) {
+	switch cfg.Mode {
+	case "", ModeClassic:
+		return Classic{Config: cfg}, nil
+	case ModeFlowtime:
+		return Flowtime{
+			BreakRatio: cfg.Flowtime.BreakRatio,
+			MinBreak:   time.Duration(cfg.Flowtime.MinBreak),
+			MaxBreak:   time.Duration(cfg.Flowtime.MaxBreak),
+		}, nil
+	}
+
+	if phases, ok := cfg.Sequences[cfg.Mode]; ok {
+		return newFixed(cfg.Mode, phases)
+	}
+	if work, brk, ok := config.ParseRatio(cfg.Mode); ok {
+		return Fixed{Phases: []Phase{
+			{Name: StateWorking.Name, Kind: KindWork, Duration: work},
+			{Name: "break", Kind: KindBreak, Duration: brk},
+		}}, nil
+	}
+
+	return nil, fmt.Errorf("unknown timer mode %q", cfg.Mode)
+}
+
+func newFixed(name string, phases []config.PhaseConfig) (Fixed, error) {
+	if len(phases) == 0 {
+		return Fixed{}, fmt.Errorf("sequence %q has no phases", name)
+	}
+
+	seq := Fixed{Phases: make([]Phase, 0, len(phases))}
+	for i, pc := range phases {
+		kind, err := ParseKind(pc.Kind)
+		if err != nil {
+			return Fixed{}, fmt.Errorf("sequence %q phase %d: %v", name, i+1, err)
+		}
+		if pc.Duration <= 0 {
+			return Fixed{}, fmt.Errorf("sequence %q phase %d: duration must be positive", name, i+1)
+		}
+		phaseName := pc.Name
+		if phaseName == "" {
+			phaseName = kind.String()
+		}
+		seq.Phases = append(seq.Phases, Phase{Name: phaseName, Kind: kind, Duration: time.Duration(pc.Duration)})
+	}
+	return seq, nil
+}
+
+// ParseKind parses the kind of a configured phase, "work" or "break"
+func ParseKind(s string) (Kind, error) {
+	switch s {
+	case "work":
+		return KindWork, nil
+	case "break":
+		return KindBreak, nil
+	default:
+		return KindIdle, fmt.Errorf("phase kind must be \"work\" or \"break\", got %q", s)
+	}
+}
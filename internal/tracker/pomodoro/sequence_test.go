@@ -0,0 +1,151 @@
+package pomodoro_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AndriyBarskyi/gotrack/internal/config"
+	"github.com/AndriyBarskyi/gotrack/internal/tracker/pomodoro"
+)
+
+func TestClassic(t *testing.T) {
+	seq := pomodoro.Classic{Config: testConfig()}
+	work := seq.Next(pomodoro.Progress{})
+	assert.Equal(t, pomodoro.StateWorking, work.State())
+	assert.Equal(t, 25*time.Minute, work.Duration)
+
+	short := seq.Next(pomodoro.Progress{Completed: work, Cycles: 1, Index: 1})
+	assert.Equal(t, pomodoro.StateShortBreak, short.State())
+
+	long := seq.Next(pomodoro.Progress{Completed: work, Cycles: 4, Index: 7})
+	assert.Equal(t, pomodoro.StateLongBreak, long.State())
+	assert.Equal(t, 15*time.Minute, long.Duration)
+
+	assert.Equal(t, work, seq.Next(pomodoro.Progress{Completed: long, Cycles: 4, Index: 8}))
+}
+
+func TestFlowtime(t *testing.T) {
+	seq := pomodoro.Flowtime{BreakRatio: 5, MinBreak: 2 * time.Minute, MaxBreak: 20 * time.Minute}
+	work := seq.Next(pomodoro.Progress{})
+	assert.True(t, work.OpenEnded())
+	assert.Equal(t, pomodoro.KindWork, work.Kind)
+
+	tests := []struct {
+		name     string
+		worked   time.Duration
+		expected time.Duration
+	}{
+		{name: "proportional", worked: 50 * time.Minute, expected: 10 * time.Minute},
+		{name: "minimum", worked: 3 * time.Minute, expected: 2 * time.Minute},
+		{name: "maximum", worked: 3 * time.Hour, expected: 20 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			brk := seq.Next(pomodoro.Progress{Completed: work, Elapsed: tt.worked, Cycles: 1, Index: 1})
+			assert.Equal(t, pomodoro.KindBreak, brk.Kind)
+			assert.Equal(t, tt.expected, brk.Duration)
+		})
+	}
+
+	t.Run("no minimum", func(t *testing.T) {
+		seq := pomodoro.Flowtime{BreakRatio: 5}
+		brk := seq.Next(pomodoro.Progress{Completed: work, Elapsed: 2 * time.Second, Cycles: 1, Index: 1})
+		assert.False(t, brk.OpenEnded(), "A break always ends")
+		assert.Equal(t, time.Second, brk.Duration)
+	})
+}
+
+func TestNewSequence(t *testing.T) {
+	cfg := testConfig()
+	cfg.Sequences = map[string][]config.PhaseConfig{
+		"writing": {
+			{Name: "draft", Kind: "work", Duration: config.Duration(45 * time.Minute)},
+			{Name: "walk", Kind: "break", Duration: config.Duration(10 * time.Minute)},
+			{Kind: "work", Duration: config.Duration(20 * time.Minute)},
+		},
+		"broken": {
+			{Name: "nap", Kind: "sleep", Duration: config.Duration(time.Minute)},
+		},
+	}
+
+	t.Run("classic by default", func(t *testing.T) {
+		seq, err := pomodoro.NewSequence(cfg)
+		require.NoError(t, err)
+		assert.IsType(t, pomodoro.Classic{}, seq)
+	})
+
+	t.Run("flowtime", func(t *testing.T) {
+		cfg := *cfg
+		cfg.Mode = pomodoro.ModeFlowtime
+		seq, err := pomodoro.NewSequence(&cfg)
+		require.NoError(t, err)
+		assert.IsType(t, pomodoro.Flowtime{}, seq)
+	})
+
+	t.Run("ratio", func(t *testing.T) {
+		cfg := *cfg
+		cfg.Mode = "52/17"
+		seq, err := pomodoro.NewSequence(&cfg)
+		require.NoError(t, err)
+
+		work := seq.Next(pomodoro.Progress{})
+		brk := seq.Next(pomodoro.Progress{Completed: work, Index: 1})
+		assert.Equal(t, 52*time.Minute, work.Duration)
+		assert.Equal(t, 17*time.Minute, brk.Duration)
+		assert.Equal(t, work, seq.Next(pomodoro.Progress{Completed: brk, Index: 2}))
+	})
+
+	t.Run("custom sequence", func(t *testing.T) {
+		cfg := *cfg
+		cfg.Mode = "writing"
+		seq, err := pomodoro.NewSequence(&cfg)
+		require.NoError(t, err)
+
+		var names []string
+		for i := 0; i < 4; i++ {
+			names = append(names, seq.Next(pomodoro.Progress{Index: i}).Name)
+		}
+		assert.Equal(t, []string{"draft", "walk", "work", "draft"}, names)
+	})
+
+	t.Run("invalid phase kind", func(t *testing.T) {
+		cfg := *cfg
+		cfg.Mode = "broken"
+		_, err := pomodoro.NewSequence(&cfg)
+		assert.ErrorContains(t, err, `sequence "broken" phase 1`)
+	})
+
+	t.Run("unknown mode", func(t *testing.T) {
+		cfg := *cfg
+		cfg.Mode = "52/0"
+		_, err := pomodoro.NewSequence(&cfg)
+		assert.ErrorContains(t, err, "unknown timer mode")
+	})
+}
+
+func TestFlowtimeTimer(t *testing.T) {
+	clock := newFakeClock()
+	cfg := testConfig()
+	p := pomodoro.NewWithSequence(cfg, pomodoro.Flowtime{BreakRatio: 5})
+	p.SetClock(clock.Now)
+
+	require.NoError(t, p.Start())
+	p.Tick(clock.Advance(2 * time.Hour))
+	assert.True(t, p.State().IsWork(), "Open-ended work should not end on its own")
+	assert.Zero(t, p.Remaining())
+
+	p.Pause()
+	clock.Advance(time.Hour)
+	require.NoError(t, p.Start())
+	assert.Equal(t, 2*time.Hour, p.Elapsed(), "Paused time should not count as worked")
+
+	p.Skip()
+	assert.Equal(t, pomodoro.State{Kind: pomodoro.KindBreak, Name: "break"}, p.State())
+	assert.Equal(t, 24*time.Minute, p.Remaining())
+	assert.Equal(t, 1, p.Cycles())
+	p.Stop()
+}
@@ -1,35 +1,75 @@
 package pomodoro
 
-// State represents the current state of the Pomodoro timer
-type State int
+// Kind is the kind of a timer state
+type Kind int
 
 const (
+	// KindIdle means the timer is not running
+	KindIdle Kind = iota
+	// KindWork means a work phase is active
+	KindWork
+	// KindBreak means a break phase is active
+	KindBreak
+	// KindPaused means the timer is paused
+	KindPaused
+)
+
+// String returns a human-readable representation of the kind
+func (k Kind) String() string {
+	switch k {
+	case KindIdle:
+		return "idle"
+	case KindWork:
+		return "work"
+	case KindBreak:
+		return "break"
+	case KindPaused:
+		return "paused"
+	default:
+		return "unknown"
+	}
+}
+
+// State represents the current state of the Pomodoro timer.
+// Running states carry the name of their phase, so a sequence is not limited
+// to the classic work, short break and long break phases.
+type State struct {
+	Kind Kind
+	Name string
+}
+
+var (
 	// StateIdle means the timer is not running
-	StateIdle State = iota
-	// StateWorking means a work session is active
-	StateWorking
+	StateIdle = State{Kind: KindIdle, Name: "idle"}
+	// StateWorking means a classic work session is active
+	StateWorking = State{Kind: KindWork, Name: "working"}
 	// StateShortBreak means a short break is active
-	StateShortBreak
+	StateShortBreak = State{Kind: KindBreak, Name: "short break"}
 	// StateLongBreak means a long break is active
-	StateLongBreak
+	StateLongBreak = State{Kind: KindBreak, Name: "long break"}
 	// StatePaused means the timer is paused
-	StatePaused
+	StatePaused = State{Kind: KindPaused, Name: "paused"}
 )
 
 // String returns a human-readable representation of the state
 func (s State) String() string {
-	switch s {
-	case StateIdle:
-		return "idle"
-	case StateWorking:
-		return "working"
-	case StateShortBreak:
-		return "short break"
-	case StateLongBreak:
-		return "long break"
-	case StatePaused:
-		return "paused"
-	default:
-		return "unknown"
+	if s.Name == "" {
+		return s.Kind.String()
 	}
+	return s.Name
+}
+
+// IsWork reports whether the state is a work phase
+func (s State) IsWork() bool {
+	return s.Kind == KindWork
+}
+
+// IsBreak reports whether the state is a break phase
+func (s State) IsBreak() bool {
+	return s.Kind == KindBreak
+}
+
+// isRunning reports whether the state is an active phase
+func (s State) isRunning() bool {
+	return s.Kind == KindWork || s.Kind == KindBreak
 }
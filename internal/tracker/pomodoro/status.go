@@ -0,0 +1,99 @@
+package pomodoro
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// StatusTTL is how long a status snapshot stays valid without being refreshed.
+// The timer loop rewrites the snapshot well within this interval, so an older
+// one was left behind by a process that no longer runs.
+const StatusTTL = 30 * time.Second
+
+// ErrNoStatus is returned when no running timer has published its status
+var ErrNoStatus = errors.New("no pomodoro is running")
+
+// Status is a snapshot of a running timer, shared with other gotrack
+// processes through a status file
+type Status struct {
+	Task       string        `json:"task"`
+	State      string        `json:"state"`
+	Kind       string        `json:"kind"`
+	PhaseStart time.Time     `json:"phase_start"`
+	Remaining  time.Duration `json:"remaining"`
+	Elapsed    time.Duration `json:"elapsed"`
+	Cycles     int           `json:"cycles"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+// Status returns a snapshot of the timer for the given task
+func (p *Pomodoro) Status(task string) Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return Status{
+		Task:       task,
+		State:      p.state.String(),
+		Kind:       p.state.Kind.String(),
+		PhaseStart: p.phaseStart,
+		Remaining:  p.remainingLocked(),
+		Elapsed:    p.elapsedLocked(),
+		Cycles:     p.cycles,
+		UpdatedAt:  p.now(),
+	}
+}
+
+// IsWorking reports whether the snapshot was taken during a work interval
+func (s *Status) IsWorking() bool {
+	return s.Kind == KindWork.String()
+}
+
+// WriteStatus atomically replaces the status file at path
+func WriteStatus(path string, s Status) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pomodoro status: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".pomodoro-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write pomodoro status: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write pomodoro status: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write pomodoro status: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// ReadStatus reads the status file at path. It returns ErrNoStatus when the
+// file does not exist or was not refreshed within StatusTTL.
+func ReadStatus(path string) (*Status, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoStatus
+		}
+		return nil, fmt.Errorf("failed to read pomodoro status: %w", err)
+	}
+
+	var s Status
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse pomodoro status: %w", err)
+	}
+
+	if time.Since(s.UpdatedAt) > StatusTTL {
+		return nil, ErrNoStatus
+	}
+
+	return &s, nil
+}
@@ -3,6 +3,7 @@ package tracker
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/AndriyBarskyi/gotrack/internal/models"
@@ -23,7 +24,13 @@ func NewSessionManager(storage storage.Storage) *SessionManager {
 
 // Start starts a new session.
 func (sm *SessionManager) Start(task string) (*models.Session, error) {
-	if task == "" {
+	return sm.StartSession(models.Session{Task: task})
+}
+
+// StartSession starts a new session for the task, project, tags and note of
+// the given template. The times of the template are ignored.
+func (sm *SessionManager) StartSession(template models.Session) (*models.Session, error) {
+	if template.Task == "" {
 		return nil, fmt.Errorf("task name cannot be empty")
 	}
 
@@ -37,7 +44,10 @@ func (sm *SessionManager) Start(task string) (*models.Session, error) {
 	}
 
 	session := &models.Session{
-		Task:      task,
+		Task:      template.Task,
+		Project:   template.Project,
+		Tags:      template.Tags,
+		Note:      template.Note,
 		StartTime: time.Now(),
 	}
 
@@ -50,6 +60,12 @@ func (sm *SessionManager) Start(task string) (*models.Session, error) {
 
 // Finish ends the last session.
 func (sm *SessionManager) Finish() (*models.Session, error) {
+	return sm.FinishWithNote("")
+}
+
+// FinishWithNote ends the last session and adds a note to it, after the note
+// it was started with if any.
+func (sm *SessionManager) FinishWithNote(note string) (*models.Session, error) {
 	sessions, err := sm.storage.GetAll()
 	if err != nil {
 		return nil, fmt.Errorf("error retrieving sessions: %v", err)
@@ -65,6 +81,11 @@ func (sm *SessionManager) Finish() (*models.Session, error) {
 	}
 
 	lastSession.EndTime = time.Now()
+	if note != "" && lastSession.Note != "" {
+		lastSession.Note += "; " + note
+	} else if note != "" {
+		lastSession.Note = note
+	}
 
 	err = sm.storage.Save(&lastSession)
 	if err != nil {
@@ -83,13 +104,10 @@ func (sm *SessionManager) GetLast() (*models.Session, error) {
 	return session, nil
 }
 
-// GetTodaySessions returns all sessions that started today.
-func (sm *SessionManager) GetTodaySessions() ([]models.Session, error) {
-	now := time.Now()
-	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
-
-	sessions, err := sm.storage.GetByDateRange(startOfDay, endOfDay)
+// GetTodaySessions returns all sessions that were running today, from
+// startOfDay to endOfDay, including those that started the day before.
+func (sm *SessionManager) GetTodaySessions(startOfDay, endOfDay time.Time) ([]models.Session, error) {
+	sessions, err := sm.storage.GetByDateRange(startOfDay, endOfDay, storage.OverlapsRange)
 	if err != nil {
 		return nil, fmt.Errorf("error getting today's sessions: %w", err)
 	}
@@ -123,10 +141,18 @@ func (sm *SessionManager) FormatSession(ssn models.Session, i int, ssns []models
 	if !ssn.EndTime.IsZero() {
 		endTime = ssn.EndTime.Format("2006-01-02 15:04:05")
 	}
-	return fmt.Sprintf("Task: %s (%d/%d)\nStart time: %s\nEnd time: %s\n\n",
+	details := ""
+	if ssn.Project != "" {
+		details += fmt.Sprintf("Project: %s\n", ssn.Project)
+	}
+	if len(ssn.Tags) > 0 {
+		details += fmt.Sprintf("Tags: %s\n", strings.Join(ssn.Tags, ", "))
+	}
+	return fmt.Sprintf("Task: %s (%d/%d)\n%sStart time: %s\nEnd time: %s\n\n",
 		ssn.Task,
 		i+1,
 		len(ssns),
+		details,
 		ssn.StartTime.Format("2006-01-02 15:04:05"),
 		endTime,
 	)
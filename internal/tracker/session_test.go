@@ -3,13 +3,16 @@ package tracker_test
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/AndriyBarskyi/gotrack/internal/models"
+	"github.com/AndriyBarskyi/gotrack/internal/storage"
 	"github.com/AndriyBarskyi/gotrack/internal/tracker"
 )
 
@@ -39,8 +42,8 @@ func (m *MockStorage) GetAll() ([]models.Session, error) {
 	return args.Get(0).([]models.Session), args.Error(1)
 }
 
-func (m *MockStorage) GetByDateRange(start, end time.Time) ([]models.Session, error) {
-	args := m.Called(start, end)
+func (m *MockStorage) GetByDateRange(start, end time.Time, match ...storage.RangeMatch) ([]models.Session, error) {
+	args := m.Called(start, end, match)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -135,6 +138,27 @@ func TestSessionManager_Start(t *testing.T) {
 	}
 }
 
+func TestSessionManager_StartSession(t *testing.T) {
+	mockStorage := new(MockStorage)
+	mockStorage.On("GetLast").Return((*models.Session)(nil), models.ErrNoSessions).Once()
+	mockStorage.On("Save", mock.AnythingOfType("*models.Session")).Return(nil).Once()
+
+	sm := tracker.NewSessionManager(mockStorage)
+	session, err := sm.StartSession(models.Session{
+		Task:      "test task",
+		Project:   "gotrack",
+		Tags:      []string{"go", "oss"},
+		StartTime: time.Now().Add(-time.Hour),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "test task", session.Task)
+	assert.Equal(t, "gotrack", session.Project)
+	assert.Equal(t, []string{"go", "oss"}, session.Tags)
+	assert.WithinDuration(t, time.Now(), session.StartTime, time.Second, "Start time of the template should be ignored")
+	mockStorage.AssertExpectations(t)
+}
+
 func TestSessionManager_Finish(t *testing.T) {
 	now := time.Now()
 	tests := []struct {
@@ -211,6 +235,59 @@ func TestSessionManager_Finish(t *testing.T) {
 	}
 }
 
+func TestSessionManager_StartFinish_FileStorage(t *testing.T) {
+	fs, err := storage.NewFileStorage(filepath.Join(t.TempDir(), "sessions.jsonl"))
+	require.NoError(t, err)
+	sm := tracker.NewSessionManager(fs)
+
+	_, err = sm.Start("test task")
+	require.NoError(t, err)
+	finished, err := sm.Finish()
+	require.NoError(t, err)
+
+	sessions, err := sm.GetAllSessions()
+	require.NoError(t, err)
+	require.Len(t, sessions, 1, "The finished session should not leave its running record behind")
+	assert.False(t, sessions[0].IsActive())
+	assert.WithinDuration(t, finished.EndTime, sessions[0].EndTime, time.Millisecond)
+
+	_, err = sm.Start("next task")
+	assert.NoError(t, err, "A new session can start once the last one finished")
+}
+
+func TestSessionManager_FinishWithNote(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		startNote string
+		note      string
+		expected  string
+	}{
+		{name: "note on stop", note: "fixed the bug", expected: "fixed the bug"},
+		{name: "note on start", startNote: "bug #12", expected: "bug #12"},
+		{name: "notes on start and stop", startNote: "bug #12", note: "fixed", expected: "bug #12; fixed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := new(MockStorage)
+			mockStorage.On("GetAll").Return([]models.Session{{
+				Task:      "test task",
+				StartTime: now.Add(-time.Hour),
+				Note:      tt.startNote,
+			}}, nil).Once()
+			mockStorage.On("Save", mock.AnythingOfType("*models.Session")).Return(nil).Once()
+
+			sm := tracker.NewSessionManager(mockStorage)
+			session, err := sm.FinishWithNote(tt.note)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, session.Note)
+			mockStorage.AssertExpectations(t)
+		})
+	}
+}
+
 func TestSessionManager_GetLast(t *testing.T) {
 	now := time.Now()
 	tests := []struct {
@@ -437,6 +514,21 @@ func TestSessionManager_FormatSession(t *testing.T) {
 			expected: fmt.Sprintf("Task: active task (2/2)\nStart time: %s\nEnd time: \n\n",
 				startTime.Format("2006-01-02 15:04:05")),
 		},
+		{
+			name: "session with project and tags",
+			session: models.Session{
+				Task:      "tagged task",
+				Project:   "gotrack",
+				Tags:      []string{"go", "oss"},
+				StartTime: startTime,
+				EndTime:   endTime,
+			},
+			index:    0,
+			sessions: []models.Session{{Task: "tagged task"}},
+			expected: fmt.Sprintf("Task: tagged task (1/1)\nProject: gotrack\nTags: go, oss\nStart time: %s\nEnd time: %s\n\n",
+				startTime.Format("2006-01-02 15:04:05"),
+				endTime.Format("2006-01-02 15:04:05")),
+		},
 		{
 			name: "session with custom index",
 			session: models.Session{
@@ -484,21 +576,21 @@ func TestSessionManager_GetTodaySessions(t *testing.T) {
 					{Task: "task 1", StartTime: todayStart.Add(9 * time.Hour), EndTime: todayStart.Add(10 * time.Hour)},
 					{Task: "task 2", StartTime: todayStart.Add(11 * time.Hour), EndTime: todayStart.Add(12 * time.Hour)},
 				}
-				ms.On("GetByDateRange", todayStart, todayStart.Add(24*time.Hour)).Return(todaySessions, nil).Once()
+				ms.On("GetByDateRange", todayStart, todayStart.AddDate(0, 0, 1), []storage.RangeMatch{storage.OverlapsRange}).Return(todaySessions, nil).Once()
 			},
 			expectError: false,
 		},
 		{
 			name: "no sessions today",
 			setupMock: func(ms *MockStorage) {
-				ms.On("GetByDateRange", todayStart, todayStart.Add(24*time.Hour)).Return([]models.Session{}, nil).Once()
+				ms.On("GetByDateRange", todayStart, todayStart.AddDate(0, 0, 1), []storage.RangeMatch{storage.OverlapsRange}).Return([]models.Session{}, nil).Once()
 			},
 			expectError: false,
 		},
 		{
 			name: "storage error",
 			setupMock: func(ms *MockStorage) {
-				ms.On("GetByDateRange", todayStart, todayStart.Add(24*time.Hour)).
+				ms.On("GetByDateRange", todayStart, todayStart.AddDate(0, 0, 1), []storage.RangeMatch{storage.OverlapsRange}).
 					Return(([]models.Session)(nil), errors.New("storage error")).Once()
 			},
 			expectError: true,
@@ -512,7 +604,7 @@ func TestSessionManager_GetTodaySessions(t *testing.T) {
 			tt.setupMock(mockStorage)
 
 			sm := tracker.NewSessionManager(mockStorage)
-			sessions, err := sm.GetTodaySessions()
+			sessions, err := sm.GetTodaySessions(todayStart, todayStart.AddDate(0, 0, 1))
 
 			if tt.expectError {
 				assert.Error(t, err)